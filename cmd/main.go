@@ -1,20 +1,41 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
+	"pr-reviewer-service/internal/delivery"
 	"pr-reviewer-service/internal/handler"
+	"pr-reviewer-service/internal/health"
+	"pr-reviewer-service/internal/logging"
+	"pr-reviewer-service/internal/metrics"
+	"pr-reviewer-service/internal/migrate"
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/ooo"
+	"pr-reviewer-service/internal/outbox"
 	"pr-reviewer-service/internal/service"
+	"pr-reviewer-service/internal/sla"
 	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/tracing"
 
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
 func main() {
@@ -25,42 +46,257 @@ func main() {
 	dbname := getEnv("POSTGRES_DB", "pr_reviewer_db")
 	serverPort := getEnv("SERVER_PORT", "8080")
 
-	log.Println("Waiting for database...")
-	if err := waitForDB(host, port, user, password, dbname); err != nil {
-		log.Fatalf("Database not available: %v", err)
+	logger, err := logging.New(os.Stdout, getEnv("LOG_LEVEL", "info"), getEnv("LOG_FORMAT", logging.FormatJSON))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		os.Exit(1)
 	}
+	slog.SetDefault(logger)
 
-	log.Println("Running migrations...")
-	if err := runMigrations(host, port, user, password, dbname); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err := runMigrate(ctx, os.Args[2:], os.Stdout, postgresDSN(host, port, user, password, dbname))
+		stop()
+		if err != nil {
+			fatal("Migration failed", "err", err)
+		}
+		return
 	}
 
-	store, err := storage.New(host, port, user, password, dbname)
+	shutdownTracing, err := tracing.Setup(context.Background(), getEnv("OTEL_TRACES_EXPORTER", tracing.ExporterNone))
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("Failed to initialize tracing", "err", err)
 	}
-	defer store.Close()
+
+	// The server starts before storage so that the probes can report the
+	// startup; the API is only routed to once everything is set up.
+	checker := health.NewChecker()
+	var api apiGate
+	root := http.NewServeMux()
+	root.HandleFunc("GET /healthz", checker.Liveness)
+	root.HandleFunc("GET /readyz", checker.Readiness)
+	root.Handle("/", &api)
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", serverPort),
+		Handler:           root,
+		ReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:      getEnvDuration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", 60*time.Second),
+	}
+
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting server", "addr", server.Addr)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	store, err := openStorage(signals, checker, getEnv("STORAGE_BACKEND", "postgres"), host, port, user, password, dbname)
+	if err != nil {
+		fatal("Failed to initialize storage", "err", err)
+	}
+	checker.AddCheck("database", store.Ping)
 
 	svc := service.New(store)
-	h := handler.New(svc)
+
+	publisher, closePublisher, err := outboxPublisher(store)
+	if err != nil {
+		fatal("Failed to configure outbox publishers", "err", err)
+	}
+
+	// Background workers run until shutdown cancels workerCtx; workers
+	// tracks them so that shutdown can wait for their current batch.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	relayConfig := outboxConfig()
+	relay := outbox.NewRelay(store, publisher, relayConfig)
+	relay.SetHeartbeat(checker.Heartbeat("outbox_relay", relayConfig.PollInterval))
+	workers.Go(func() { relay.Run(workerCtx) })
+
+	workerConfig := deliveryConfig()
+	deliveryWorker := delivery.NewWorker(store, workerConfig)
+	deliveryWorker.SetHeartbeat(checker.Heartbeat("webhook_delivery", workerConfig.PollInterval))
+	workers.Go(func() { deliveryWorker.Run(workerCtx) })
+
+	if interval := getEnvDuration("OOO_REASSIGN_INTERVAL", 0); interval > 0 {
+		job := ooo.NewJob(svc, interval)
+		job.SetHeartbeat(checker.Heartbeat("ooo_reassign", interval))
+		workers.Go(func() { job.Run(workerCtx) })
+	} else {
+		slog.Info("OOO_REASSIGN_INTERVAL is not set, out-of-office reassign job disabled")
+	}
+
+	if interval := getEnvDuration("SLA_CHECK_INTERVAL", 0); interval > 0 {
+		job := sla.NewJob(svc, interval)
+		job.SetHeartbeat(checker.Heartbeat("sla_check", interval))
+		workers.Go(func() { job.Run(workerCtx) })
+	} else {
+		slog.Info("SLA_CHECK_INTERVAL is not set, review SLA job disabled")
+	}
+
+	h := handler.New(svc, handler.Config{
+		GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitLabWebhookToken:  os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+	})
+
+	metrics.RegisterTeamLoad(svc.TeamLoad)
 
 	r := mux.NewRouter()
+	r.Use(otelmux.Middleware(tracing.ServiceName))
+	r.Use(logging.Middleware)
+	r.Use(metrics.Middleware)
 
 	r.HandleFunc("/team/add", h.CreateTeam).Methods("POST")
 	r.HandleFunc("/team/get", h.GetTeam).Methods("GET")
 	r.HandleFunc("/team/deactivate", h.DeactivateTeam).Methods("POST")
+	r.HandleFunc("/team/update", h.UpdateTeam).Methods("POST")
+	r.HandleFunc("/team/setReviewerStrategy", h.SetReviewerStrategy).Methods("POST")
+	r.HandleFunc("/team/setCodeOwners", h.SetCodeOwners).Methods("POST")
+	r.HandleFunc("/team/codeOwners", h.GetCodeOwners).Methods("GET")
+	r.HandleFunc("/pools/add", h.CreateReviewerPool).Methods("POST")
+	r.HandleFunc("/pools/setMembers", h.SetReviewerPoolMembers).Methods("POST")
+	r.HandleFunc("/pools/get", h.GetReviewerPool).Methods("GET")
+	r.HandleFunc("/pools/list", h.ListReviewerPools).Methods("GET")
+	r.HandleFunc("/pools/delete", h.DeleteReviewerPool).Methods("POST")
 	r.HandleFunc("/users/setIsActive", h.SetUserActive).Methods("POST")
+	r.HandleFunc("/users/setMaxOpenReviews", h.SetUserMaxOpenReviews).Methods("POST")
+	r.HandleFunc("/users/linkAccount", h.LinkExternalAccount).Methods("POST")
+	r.HandleFunc("/users/unavailability/add", h.CreateUnavailability).Methods("POST")
+	r.HandleFunc("/users/unavailability/list", h.ListUnavailability).Methods("GET")
+	r.HandleFunc("/users/unavailability/delete", h.DeleteUnavailability).Methods("POST")
 	r.HandleFunc("/pullRequest/create", h.CreatePR).Methods("POST")
 	r.HandleFunc("/pullRequest/merge", h.MergePR).Methods("POST")
+	r.HandleFunc("/pullRequest/mergeOverrides", h.ListMergeOverrides).Methods("GET")
+	r.HandleFunc("/pullRequest/ready", h.MarkReady).Methods("POST")
+	r.HandleFunc("/pullRequest/close", h.ClosePR).Methods("POST")
+	r.HandleFunc("/pullRequest/reopen", h.ReopenPR).Methods("POST")
 	r.HandleFunc("/pullRequest/reassign", h.ReassignReviewer).Methods("POST")
+	r.HandleFunc("/pullRequest/review", h.SubmitReview).Methods("POST")
+	r.HandleFunc("/pullRequest/history", h.GetPRHistory).Methods("GET")
+	r.HandleFunc("/pullRequest/underReviewed", h.ListUnderReviewedPRs).Methods("GET")
 	r.HandleFunc("/users/getReview", h.GetUserReviews).Methods("GET")
 	r.HandleFunc("/statistics", h.GetStatistics).Methods("GET")
+	r.HandleFunc("/statistics/fairness", h.GetFairnessReport).Methods("GET")
+	r.HandleFunc("/sla/breaches", h.ListSLABreaches).Methods("GET")
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
+	r.HandleFunc("/subscriptions/add", h.CreateWebhookSubscription).Methods("POST")
+	r.HandleFunc("/subscriptions/list", h.ListWebhookSubscriptions).Methods("GET")
+	r.HandleFunc("/subscriptions/delete", h.DeleteWebhookSubscription).Methods("POST")
+	r.HandleFunc("/subscriptions/deliveries", h.ListWebhookDeliveries).Methods("GET")
+	r.HandleFunc("/subscriptions/attempts", h.ListWebhookDeliveryAttempts).Methods("GET")
+	r.HandleFunc("/subscriptions/deadLetters", h.ListWebhookDeadLetters).Methods("GET")
+
+	if os.Getenv("GITHUB_WEBHOOK_SECRET") != "" {
+		r.HandleFunc("/webhooks/github", h.GitHubWebhook).Methods("POST")
+	} else {
+		slog.Info("GITHUB_WEBHOOK_SECRET is not set, /webhooks/github is disabled")
+	}
+	if os.Getenv("GITLAB_WEBHOOK_TOKEN") != "" {
+		r.HandleFunc("/webhooks/gitlab", h.GitLabWebhook).Methods("POST")
+	} else {
+		slog.Info("GITLAB_WEBHOOK_TOKEN is not set, /webhooks/gitlab is disabled")
+	}
+
+	api.set(r)
+	checker.SetPhase(health.PhaseReady)
+	slog.Info("Service is ready")
+
+	exitCode := 0
+	select {
+	case <-signals.Done():
+		slog.Info("Shutdown signal received, draining")
+	case err := <-serverErr:
+		slog.Error("Server failed", "err", err)
+		exitCode = 1
+	}
+	// A second signal kills the process the default way.
+	stopSignals()
+	checker.SetPhase(health.PhaseShuttingDown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second))
+	if err := shutdown(ctx, server, stopWorkers, &workers, relay, func() {
+		closePublisher()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "err", err)
+		}
+		if err := store.Close(); err != nil {
+			slog.Error("Failed to close storage", "err", err)
+		}
+	}); err != nil {
+		slog.Error("Shutdown did not complete cleanly", "err", err)
+		exitCode = 1
+	}
+	cancel()
+	slog.Info("Server stopped")
+	os.Exit(exitCode)
+}
+
+// apiGate answers 503 until the API router is installed with set.
+type apiGate struct {
+	handler atomic.Pointer[http.Handler]
+}
+
+func (g *apiGate) set(h http.Handler) {
+	g.handler.Store(&h)
+}
+
+func (g *apiGate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := g.handler.Load()
+	if h == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(model.ErrorResponse{Error: model.ErrorDetail{
+			Code:    model.ErrNotReady,
+			Message: "service is starting",
+		}})
+		return
+	}
+	(*h).ServeHTTP(w, r)
+}
+
+// shutdown stops the service in dependency order within the deadline of
+// ctx: the server stops accepting connections and drains in-flight requests,
+// then the background workers finish their current batch, the outbox is
+// flushed so that events of the last requests are published, and finally
+// release closes publishers, traces and storage. release runs even when an
+// earlier step runs out of time.
+func shutdown(ctx context.Context, server *http.Server, stopWorkers context.CancelFunc, workers *sync.WaitGroup,
+	relay *outbox.Relay, release func()) error {
+	defer release()
+
+	var errs []error
+	if err := server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("drain requests: %w", err))
+	}
+
+	stopWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return errors.Join(append(errs, fmt.Errorf("stop workers: %w", ctx.Err()))...)
+	}
 
-	addr := fmt.Sprintf(":%s", serverPort)
-	log.Printf("Starting server on %s", addr)
-	if err := http.ListenAndServe(addr, r); err != nil {
-		log.Fatalf("Server failed: %v", err)
+	if err := relay.Flush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("flush outbox: %w", err))
 	}
+	return errors.Join(errs...)
+}
+
+// fatal logs msg with args at error level and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -70,51 +306,254 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func waitForDB(host, port, user, password, dbname string) error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		fatal("Invalid environment variable", "key", key, "err", err)
+	}
+	return n
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		fatal("Invalid environment variable", "key", key, "err", err)
+	}
+	return b
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fatal("Invalid environment variable", "key", key, "err", err)
+	}
+	return d
+}
+
+func outboxConfig() outbox.Config {
+	cfg := outbox.DefaultConfig()
+	cfg.PollInterval = getEnvDuration("OUTBOX_POLL_INTERVAL", cfg.PollInterval)
+	cfg.BaseBackoff = getEnvDuration("OUTBOX_BASE_BACKOFF", cfg.BaseBackoff)
+	cfg.MaxBackoff = getEnvDuration("OUTBOX_MAX_BACKOFF", cfg.MaxBackoff)
+	return cfg
+}
+
+// outboxPublisher builds the publisher chain from OUTBOX_PUBLISHERS, a comma
+// separated list of "webhooks", "log" and "http". The returned func releases
+// any files the publishers opened.
+func outboxPublisher(store storage.Repository) (outbox.EventPublisher, func(), error) {
+	var publishers outbox.MultiPublisher
+	closers := []io.Closer{}
+	closeAll := func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+
+	for _, name := range strings.Split(getEnv("OUTBOX_PUBLISHERS", "webhooks"), ",") {
+		switch strings.TrimSpace(name) {
+		case "webhooks":
+			publishers = append(publishers, outbox.NewWebhookPublisher(store))
+		case "log":
+			var w io.Writer = os.Stdout
+			if path := os.Getenv("OUTBOX_LOG_FILE"); path != "" {
+				f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+				if err != nil {
+					closeAll()
+					return nil, nil, err
+				}
+				closers = append(closers, f)
+				w = f
+			}
+			publishers = append(publishers, outbox.NewLogPublisher(w))
+		case "http":
+			url := os.Getenv("OUTBOX_HTTP_URL")
+			if url == "" {
+				closeAll()
+				return nil, nil, fmt.Errorf("OUTBOX_HTTP_URL is required for the http publisher")
+			}
+			publishers = append(publishers, outbox.NewHTTPPublisher(url, os.Getenv("OUTBOX_HTTP_SECRET"),
+				getEnvDuration("OUTBOX_HTTP_TIMEOUT", 10*time.Second)))
+		case "":
+		default:
+			closeAll()
+			return nil, nil, fmt.Errorf("unknown outbox publisher %q", name)
+		}
+	}
+	return publishers, closeAll, nil
+}
+
+func deliveryConfig() delivery.Config {
+	cfg := delivery.DefaultConfig()
+	cfg.PollInterval = getEnvDuration("WEBHOOK_POLL_INTERVAL", cfg.PollInterval)
+	cfg.RequestTimeout = getEnvDuration("WEBHOOK_REQUEST_TIMEOUT", cfg.RequestTimeout)
+	cfg.MaxAttempts = getEnvInt("WEBHOOK_MAX_ATTEMPTS", cfg.MaxAttempts)
+	cfg.BaseBackoff = getEnvDuration("WEBHOOK_BASE_BACKOFF", cfg.BaseBackoff)
+	cfg.MaxBackoff = getEnvDuration("WEBHOOK_MAX_BACKOFF", cfg.MaxBackoff)
+	return cfg
+}
+
+func openStorage(ctx context.Context, checker *health.Checker, backend, host, port, user, password, dbname string) (storage.Repository, error) {
+	switch backend {
+	case "memory":
+		slog.Warn("Using in-memory storage, data will not be persisted")
+		return storage.NewMemory(), nil
+	case "postgres":
+		return openPostgres(ctx, checker, host, port, user, password, dbname)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
+
+func openPostgres(ctx context.Context, checker *health.Checker, host, port, user, password, dbname string) (storage.Repository, error) {
+	slog.Info("Waiting for database...")
+	if err := waitForDB(ctx, host, port, user, password, dbname); err != nil {
+		return nil, fmt.Errorf("database not available: %v", err)
+	}
+
+	// The migrator keeps its own small pool for the readiness check for as
+	// long as the process runs.
+	db, err := sql.Open("postgres", postgresDSN(host, port, user, password, dbname))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(2)
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if getEnvBool("MIGRATE_ON_START", true) {
+		slog.Info("Running migrations...")
+		checker.SetPhase(health.PhaseMigrating)
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %v", err)
+		}
+		slog.Info("Migrations completed successfully", "applied", len(applied), "version", migrator.Latest())
+	}
+	checker.AddCheck("migrations", migrator.Check)
+
+	store, err := storage.New(host, port, user, password, dbname)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+	return store, nil
+}
+
+func postgresDSN(host, port, user, password, dbname string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
+}
+
+// waitForDB pings the database once a second for up to 30 seconds, or
+// until ctx is cancelled.
+func waitForDB(ctx context.Context, host, port, user, password, dbname string) error {
+	connStr := postgresDSN(host, port, user, password, dbname)
 
 	for i := 0; i < 30; i++ {
 		db, err := sql.Open("postgres", connStr)
 		if err == nil {
-			if err := db.Ping(); err == nil {
+			if err := db.PingContext(ctx); err == nil {
 				db.Close()
 				return nil
 			}
 			db.Close()
 		}
-		time.Sleep(1 * time.Second)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
 	}
 	return fmt.Errorf("database not ready after 30 seconds")
 }
 
-func runMigrations(host, port, user, password, dbname string) error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+func newMigrator(db *sql.DB) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(os.DirFS("migrations"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %v", err)
+	}
+	return migrate.New(db, migrations), nil
+}
+
+const migrateUsage = "usage: service migrate up | down [steps] | status"
 
-	db, err := sql.Open("postgres", connStr)
+// runMigrate implements the "migrate" subcommand. "down" rolls back one
+// migration unless told otherwise; "status" prints a table to out.
+func runMigrate(ctx context.Context, args []string, out io.Writer, dsn string) error {
+	if len(args) == 0 || len(args) > 2 || (len(args) == 2 && args[0] != "down") {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return err
 	}
 	defer db.Close()
-
-	migrationFiles, err := filepath.Glob("migrations/*.sql")
+	migrator, err := newMigrator(db)
 	if err != nil {
 		return err
 	}
 
-	for _, file := range migrationFiles {
-		log.Printf("Applying migration: %s", file)
-		content, err := os.ReadFile(file)
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to read migration %s: %v", file, err)
+			return err
 		}
-
-		if _, err := db.Exec(string(content)); err != nil {
-			return fmt.Errorf("failed to apply migration %s: %v", file, err)
+		slog.Info("Migrations completed successfully", "applied", len(applied), "version", migrator.Latest())
+	case "down":
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
 		}
+		slog.Info("Migrations rolled back", "count", len(rolledBack))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(out, statuses)
+	default:
+		return errors.New(migrateUsage)
 	}
-
-	log.Println("Migrations completed successfully")
 	return nil
 }
+
+func printMigrationStatus(out io.Writer, statuses []migrate.Status) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", "-"
+		if s.AppliedAt != nil {
+			state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		switch {
+		case s.Modified:
+			state = "modified"
+		case s.Missing:
+			state = "missing"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,157 @@
+// Package codeowners parses ownership rules written in the GitHub
+// CODEOWNERS format and matches file paths against them.
+package codeowners
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule is one non-empty line of a CODEOWNERS file. Owners may be empty,
+// which removes ownership for paths that match it.
+type Rule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	Line    int      `json:"line"`
+
+	re *regexp.Regexp
+}
+
+// Ruleset is an ordered list of rules. Later rules take precedence.
+type Ruleset []Rule
+
+// Parse reads CODEOWNERS content. Blank lines and comments are skipped;
+// a pattern starting with "#" must be escaped as "\#". Negation ("!") and
+// character ranges ("[ ]") are rejected, as GitHub does not support them.
+func Parse(content string) (Ruleset, error) {
+	rules := Ruleset{}
+	for i, line := range strings.Split(content, "\n") {
+		lineNo := i + 1
+		line = stripComment(strings.TrimSpace(line))
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		pattern := strings.TrimPrefix(fields[0], `\`)
+		re, err := compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		owners := fields[1:]
+		for _, owner := range owners {
+			if !validOwner(owner) {
+				return nil, fmt.Errorf("line %d: invalid owner %q", lineNo, owner)
+			}
+		}
+
+		rules = append(rules, Rule{Pattern: pattern, Owners: owners, Line: lineNo, re: re})
+	}
+	return rules, nil
+}
+
+// Match returns the last rule matching path, since the last match wins.
+func (rs Ruleset) Match(path string) (Rule, bool) {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].re.MatchString(path) {
+			return rs[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// Owners returns the owners of path, or nil if nobody owns it.
+func (rs Ruleset) Owners(path string) []string {
+	rule, ok := rs.Match(path)
+	if !ok {
+		return nil
+	}
+	return rule.Owners
+}
+
+// stripComment removes a trailing comment. A "#" only starts a comment at
+// the beginning of the line or after whitespace, and "\#" is literal.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] != '#' {
+			continue
+		}
+		if i > 0 && line[i-1] == '\\' {
+			continue
+		}
+		if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+func validOwner(owner string) bool {
+	if strings.HasPrefix(owner, "@") {
+		name := owner[1:]
+		return name != "" && !strings.HasPrefix(name, "/") && !strings.HasSuffix(name, "/") && strings.Count(name, "/") <= 1
+	}
+	// An e-mail address.
+	at := strings.Index(owner, "@")
+	return at > 0 && at < len(owner)-1
+}
+
+// compile translates a gitignore-style pattern into a regular expression
+// over slash-separated paths relative to the repository root.
+func compile(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") {
+		return nil, fmt.Errorf("negation pattern %q is not supported", pattern)
+	}
+	if strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("character range in pattern %q is not supported", pattern)
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	// A leading or inner slash anchors the pattern to the repository root;
+	// otherwise it matches at any depth.
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern %q", pattern)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		// "docs/" owns everything inside any docs directory.
+		b.WriteString("/.*")
+	case p == "*" || strings.HasSuffix(p, "/*"):
+		// "docs/*" owns files directly in docs but not in its subdirectories.
+	default:
+		// A pattern naming a directory also owns everything below it.
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,88 @@
+package codeowners
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sample = `# Default owners for everything in the repo.
+*       @global-owner
+
+# Later matches take precedence.
+*.js    @js-owner #This is an inline comment.
+*.go    @org/backend
+
+/build/logs/ @doctocat
+docs/*  docs@example.com
+apps/   @octocat
+/scripts/ @doctocat @octocat
+**/logs @logs-owner
+/apps/github
+\#notes @hash-owner
+`
+
+func TestMatch(t *testing.T) {
+	rules, err := Parse(sample)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	cases := []struct {
+		path   string
+		owners []string
+	}{
+		{"README.md", []string{"@global-owner"}},
+		{"web/app.js", []string{"@js-owner"}},
+		{"/cmd/main.go", []string{"@org/backend"}},
+		{"build/logs/out.txt", []string{"@logs-owner"}},
+		{"build/logs/2026/out.txt", []string{"@logs-owner"}},
+		{"docs/getting-started.md", []string{"docs@example.com"}},
+		{"docs/build-app/troubleshooting.md", []string{"@global-owner"}},
+		{"apps/web/index.html", []string{"@octocat"}},
+		{"services/apps/main.py", []string{"@octocat"}},
+		{"apps/github/index.html", []string{}},
+		{"scripts/deploy.sh", []string{"@doctocat", "@octocat"}},
+		{"deep/nested/logs", []string{"@logs-owner"}},
+		{"#notes", []string{"@hash-owner"}},
+	}
+	for _, tc := range cases {
+		if got := rules.Owners(tc.path); !reflect.DeepEqual(got, tc.owners) {
+			t.Errorf("Owners(%q) = %v, want %v", tc.path, got, tc.owners)
+		}
+	}
+}
+
+func TestMatchWithoutDefault(t *testing.T) {
+	rules, err := Parse("/src/**/*.go @gopher\n")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if _, ok := rules.Match("README.md"); ok {
+		t.Error("Expected no rule to match README.md")
+	}
+	for _, path := range []string{"src/main.go", "src/a/b/c.go"} {
+		if rule, ok := rules.Match(path); !ok || rule.Line != 1 {
+			t.Errorf("Expected line 1 to match %s, got %+v", path, rule)
+		}
+	}
+	if _, ok := rules.Match("lib/src/main.go"); ok {
+		t.Error("Anchored pattern must not match below the root")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"Negation":  "!*.go @gopher",
+		"Range":     "*.[ch] @c-owner",
+		"BadOwner":  "*.go gopher",
+		"EmptyTeam": "*.go @org/",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(content); err == nil {
+				t.Errorf("Expected %q to be rejected", content)
+			}
+		})
+	}
+}
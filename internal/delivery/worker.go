@@ -0,0 +1,182 @@
+// Package delivery sends queued domain events to outgoing webhook
+// subscribers, retrying failed deliveries with exponential backoff.
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pr-reviewer-service/internal/health"
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/retry"
+	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/tracing"
+	"pr-reviewer-service/internal/webhook"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxErrorBodyBytes = 1024
+
+type Config struct {
+	// PollInterval is how often the queue is checked for due deliveries.
+	PollInterval time.Duration
+	// BatchSize caps how many deliveries are claimed per poll.
+	BatchSize int
+	// RequestTimeout bounds a single HTTP attempt.
+	RequestTimeout time.Duration
+	// MaxAttempts is the number of attempts before a delivery is dead-lettered.
+	MaxAttempts int
+	// BaseBackoff is the delay after the first failure; it doubles on every
+	// further failure up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:   2 * time.Second,
+		BatchSize:      20,
+		RequestTimeout: 10 * time.Second,
+		MaxAttempts:    8,
+		BaseBackoff:    10 * time.Second,
+		MaxBackoff:     time.Hour,
+	}
+}
+
+type Worker struct {
+	store     storage.Repository
+	client    *http.Client
+	config    Config
+	now       func() time.Time
+	heartbeat *health.Heartbeat
+}
+
+func NewWorker(store storage.Repository, config Config) *Worker {
+	return &Worker{
+		store:  store,
+		client: &http.Client{Timeout: config.RequestTimeout},
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// SetHeartbeat makes the worker report the outcome of every poll to hb.
+func (w *Worker) SetHeartbeat(hb *health.Heartbeat) {
+	w.heartbeat = hb
+}
+
+// Run polls for due deliveries until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		_, err := w.ProcessDue(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Webhook delivery worker failed", "err", err)
+		}
+		w.heartbeat.Beat(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue claims one batch of due deliveries, attempts each of them and
+// returns how many were attempted.
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	// The lease must outlive a full attempt so another worker does not
+	// resend a delivery that is still in flight.
+	lease := 2 * w.config.RequestTimeout
+	deliveries, err := w.store.ClaimWebhookDeliveries(ctx, w.now(), lease, w.config.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claim deliveries: %w", err)
+	}
+
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		result := w.attempt(ctx, d)
+		if err := w.store.RecordWebhookAttempt(ctx, d.ID, result); err != nil {
+			return 0, fmt.Errorf("record attempt for delivery %d: %w", d.ID, err)
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends d once. The attempt is traced as part of the request that
+// produced the event, which also receives it in the traceparent header.
+func (w *Worker) attempt(ctx context.Context, d model.PendingWebhookDelivery) model.WebhookAttemptResult {
+	ctx, span := tracing.Start(tracing.WithTraceParent(ctx, d.TraceParent), "webhook.deliver",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("webhook.event_type", d.EventType),
+			attribute.Int64("webhook.delivery_id", d.ID),
+			attribute.Int("webhook.attempt", d.Attempts+1)))
+
+	result := model.WebhookAttemptResult{
+		Attempt:     d.Attempts + 1,
+		AttemptedAt: w.now(),
+	}
+
+	statusCode, err := w.send(ctx, d)
+	tracing.End(span, err)
+	result.Duration = w.now().Sub(result.AttemptedAt)
+	if statusCode != 0 {
+		result.StatusCode = &statusCode
+	}
+
+	switch {
+	case err == nil:
+		result.Status = model.DeliveryDelivered
+		result.NextAttemptAt = result.AttemptedAt
+	case result.Attempt >= w.config.MaxAttempts:
+		msg := err.Error()
+		result.Error = &msg
+		result.Status = model.DeliveryDead
+		result.NextAttemptAt = result.AttemptedAt
+	default:
+		msg := err.Error()
+		result.Error = &msg
+		result.Status = model.DeliveryPending
+		result.NextAttemptAt = result.AttemptedAt.Add(retry.Backoff(w.config.BaseBackoff, w.config.MaxBackoff, result.Attempt))
+	}
+	return result
+}
+
+func (w *Worker) send(ctx context.Context, d model.PendingWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Reviewer-Event", d.EventType)
+	req.Header.Set("X-Reviewer-Delivery", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Reviewer-Signature-256", webhook.Sign([]byte(d.Secret), d.Payload))
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
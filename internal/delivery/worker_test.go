@@ -0,0 +1,143 @@
+package delivery
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/tracing"
+	"pr-reviewer-service/internal/webhook"
+)
+
+func newTestWorker(store storage.Repository, now *time.Time) *Worker {
+	w := NewWorker(store, Config{
+		PollInterval:   time.Second,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		MaxAttempts:    3,
+		BaseBackoff:    time.Minute,
+		MaxBackoff:     time.Hour,
+	})
+	w.now = func() time.Time { return *now }
+	return w
+}
+
+func TestWorkerDeliversSignedEvent(t *testing.T) {
+	var gotSignature, gotEvent string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get("X-Reviewer-Signature-256")
+		gotEvent = r.Header.Get("X-Reviewer-Event")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := storage.NewMemory()
+	sub, _ := store.CreateWebhookSubscription(context.Background(), model.WebhookSubscription{
+		URL: server.URL, Secret: "s3cret", IsActive: true,
+	})
+	if _, err := store.EnqueueWebhookEvent(context.Background(), model.NewEvent(model.EventPRMerged, map[string]interface{}{"pull_request_id": "pr-1"})); err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+
+	now := time.Now()
+	n, err := newTestWorker(store, &now).ProcessDue(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Expected 1 delivery, got %d: %v", n, err)
+	}
+
+	if gotEvent != model.EventPRMerged {
+		t.Errorf("Expected event header %s, got %s", model.EventPRMerged, gotEvent)
+	}
+	if !webhook.VerifyGitHubSignature([]byte("s3cret"), gotBody, gotSignature) {
+		t.Error("Delivery signature does not verify")
+	}
+
+	deliveries, _ := store.ListWebhookDeliveries(context.Background(), sub.ID, model.DeliveryDelivered, 10)
+	if len(deliveries) != 1 || deliveries[0].Attempts != 1 {
+		t.Errorf("Expected one delivered delivery after 1 attempt, got %+v", deliveries)
+	}
+}
+
+func TestWorkerRetriesAndDeadLetters(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	store := storage.NewMemory()
+	store.CreateWebhookSubscription(context.Background(), model.WebhookSubscription{
+		URL: server.URL, Secret: "s3cret", EventTypes: []string{model.EventTeamDeactivated}, IsActive: true,
+	})
+	store.EnqueueWebhookEvent(context.Background(), model.NewEvent(model.EventPRMerged, nil))
+	store.EnqueueWebhookEvent(context.Background(), model.NewEvent(model.EventTeamDeactivated, nil))
+
+	now := time.Now()
+	worker := newTestWorker(store, &now)
+
+	// Attempt 1 fails and schedules a retry in BaseBackoff; nothing is due
+	// before that.
+	worker.ProcessDue(context.Background())
+	now = now.Add(30 * time.Second)
+	if n, _ := worker.ProcessDue(context.Background()); n != 0 {
+		t.Fatalf("Expected no due deliveries before backoff elapsed, got %d", n)
+	}
+
+	// Attempt 2 after 1m, attempt 3 after a further 2m exhausts MaxAttempts.
+	now = now.Add(time.Minute)
+	worker.ProcessDue(context.Background())
+	now = now.Add(2 * time.Minute)
+	worker.ProcessDue(context.Background())
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 HTTP attempts, got %d", got)
+	}
+
+	letters, _ := store.ListWebhookDeadLetters(context.Background(), 10)
+	if len(letters) != 1 || letters[0].EventType != model.EventTeamDeactivated {
+		t.Fatalf("Expected one dead letter for %s, got %+v", model.EventTeamDeactivated, letters)
+	}
+
+	attempts, _ := store.ListWebhookDeliveryAttempts(context.Background(), letters[0].DeliveryID)
+	if len(attempts) != 3 {
+		t.Fatalf("Expected 3 logged attempts, got %d", len(attempts))
+	}
+	if attempts[2].StatusCode == nil || *attempts[2].StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected last attempt status 502, got %v", attempts[2].StatusCode)
+	}
+}
+
+func TestWorkerPropagatesTraceContext(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	var gotTraceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceParent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := storage.NewMemory()
+	store.CreateWebhookSubscription(context.Background(), model.WebhookSubscription{URL: server.URL, IsActive: true})
+	ctx := tracing.WithTraceParent(context.Background(), "00-"+traceID+"-00f067aa0ba902b7-01")
+	if _, err := store.EnqueueWebhookEvent(ctx, model.NewEvent(model.EventPRMerged, nil)); err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+
+	now := time.Now()
+	if _, err := newTestWorker(store, &now).ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue failed: %v", err)
+	}
+	if !strings.HasPrefix(gotTraceParent, "00-"+traceID+"-") {
+		t.Errorf("Expected traceparent of trace %s, got %q", traceID, gotTraceParent)
+	}
+}
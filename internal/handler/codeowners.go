@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (h *Handler) SetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		Content  string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	owners, rules, err := h.service.SetCodeOwners(r.Context(), req.TeamName, req.Content)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid CODEOWNERS content"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code_owners": owners,
+		"rules":       rules,
+	})
+}
+
+func (h *Handler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "team_name query parameter is required")
+		return
+	}
+
+	owners, rules, err := h.service.GetCodeOwners(r.Context(), teamName)
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code_owners": owners,
+		"rules":       rules,
+	})
+}
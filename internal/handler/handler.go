@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"pr-reviewer-service/internal/model"
@@ -10,10 +11,21 @@ import (
 
 type Handler struct {
 	service *service.Service
+	config  Config
 }
 
-func New(service *service.Service) *Handler {
-	return &Handler{service: service}
+// Config holds handler settings that come from the environment.
+type Config struct {
+	// GitHubWebhookSecret is the shared secret used to verify
+	// X-Hub-Signature-256 on /webhooks/github deliveries.
+	GitHubWebhookSecret string
+	// GitLabWebhookToken is the secret token expected in X-Gitlab-Token on
+	// /webhooks/gitlab deliveries.
+	GitLabWebhookToken string
+}
+
+func New(service *service.Service, config Config) *Handler {
+	return &Handler{service: service, config: config}
 }
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -31,6 +43,16 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 	})
 }
 
+// errorMessage returns the message attached to a model.CodedError, or
+// fallback for plain error codes.
+func errorMessage(err error, fallback string) string {
+	var coded *model.CodedError
+	if errors.As(err, &coded) && coded.Message != "" {
+		return coded.Message
+	}
+	return fallback
+}
+
 func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	var team model.Team
 	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
@@ -38,12 +60,16 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdTeam, err := h.service.CreateTeam(team)
+	createdTeam, err := h.service.CreateTeam(r.Context(), team)
 	if err != nil {
 		if err.Error() == model.ErrTeamExists {
 			writeError(w, http.StatusBadRequest, model.ErrTeamExists, "team_name already exists")
 			return
 		}
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid team settings"))
+			return
+		}
 		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
 		return
 	}
@@ -60,7 +86,7 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	team, err := h.service.GetTeam(teamName)
+	team, err := h.service.GetTeam(r.Context(), teamName)
 	if err != nil {
 		if err.Error() == model.ErrNotFound {
 			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
@@ -73,6 +99,64 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, team)
 }
 
+func (h *Handler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		model.TeamSettingsUpdate
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	team, err := h.service.UpdateTeam(r.Context(), req.TeamName, req.TeamSettingsUpdate)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid team settings"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"team": team,
+	})
+}
+
+func (h *Handler) SetReviewerStrategy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName         string `json:"team_name"`
+		ReviewerStrategy string `json:"reviewer_strategy"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	team, err := h.service.SetReviewerStrategy(r.Context(), req.TeamName, req.ReviewerStrategy)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid team settings"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"team": team,
+	})
+}
+
 func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		UserID   string `json:"user_id"`
@@ -83,7 +167,7 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.service.SetUserActive(req.UserID, req.IsActive)
+	user, err := h.service.SetUserActive(r.Context(), req.UserID, req.IsActive)
 	if err != nil {
 		if err.Error() == model.ErrNotFound {
 			writeError(w, http.StatusNotFound, model.ErrNotFound, "user not found")
@@ -98,19 +182,85 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) SetUserMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID         string `json:"user_id"`
+		MaxOpenReviews *int   `json:"max_open_reviews"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	user, err := h.service.SetUserMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid max_open_reviews"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "user not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"user": user,
+	})
+}
+
+func (h *Handler) LinkExternalAccount(w http.ResponseWriter, r *http.Request) {
+	var req model.ExternalAccount
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	account, err := h.service.LinkExternalAccount(r.Context(), req)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid account"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "user not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"account": account,
+	})
+}
+
 func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PullRequestID   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		AuthorID        string `json:"author_id"`
+		PullRequestID     string   `json:"pull_request_id"`
+		PullRequestName   string   `json:"pull_request_name"`
+		AuthorID          string   `json:"author_id"`
+		ReviewersRequired int      `json:"reviewers_required"`
+		Draft             bool     `json:"draft"`
+		ChangedFiles      []string `json:"changed_files"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
 		return
 	}
 
-	pr, err := h.service.CreatePR(req.PullRequestID, req.PullRequestName, req.AuthorID)
+	pr, err := h.service.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, service.CreatePROptions{
+		ReviewersRequired: req.ReviewersRequired,
+		Draft:             req.Draft,
+		ChangedFiles:      req.ChangedFiles,
+	})
 	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid pull request options"))
+			return
+		}
 		if err.Error() == model.ErrPRExists {
 			writeError(w, http.StatusConflict, model.ErrPRExists, "PR id already exists")
 			return
@@ -119,30 +269,64 @@ func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, model.ErrNotFound, "author not found")
 			return
 		}
+		if err.Error() == model.ErrNoCandidate {
+			writeError(w, http.StatusConflict, model.ErrNoCandidate, errorMessage(err, "no reviewer with free capacity"))
+			return
+		}
+		if err.Error() == model.ErrUnderstaffed {
+			writeError(w, http.StatusConflict, model.ErrUnderstaffed, errorMessage(err, "not enough reviewers available"))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	warnings, err := h.service.StaffingWarnings(r.Context(), pr)
+	if err != nil {
 		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, map[string]interface{}{
-		"pr": pr,
+		"pr":       pr,
+		"warnings": warnings,
 	})
 }
 
 func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id"`
+		Force         bool   `json:"force"`
+		ForcedBy      string `json:"forced_by"`
+		Reason        string `json:"reason"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
 		return
 	}
 
-	pr, err := h.service.MergePR(req.PullRequestID)
+	pr, err := h.service.MergePR(r.Context(), req.PullRequestID, service.MergeOptions{
+		Force:    req.Force,
+		ForcedBy: req.ForcedBy,
+		Reason:   req.Reason,
+	})
 	if err != nil {
 		if err.Error() == model.ErrNotFound {
 			writeError(w, http.StatusNotFound, model.ErrNotFound, "PR not found")
 			return
 		}
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid merge request"))
+			return
+		}
+		if err.Error() == model.ErrNotApproved {
+			writeError(w, http.StatusConflict, model.ErrNotApproved, errorMessage(err, "PR is not approved"))
+			return
+		}
+		if err.Error() == model.ErrInvalidTransition {
+			writeError(w, http.StatusConflict, model.ErrInvalidTransition, errorMessage(err, "PR cannot be merged in its current status"))
+			return
+		}
 		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
 		return
 	}
@@ -152,17 +336,137 @@ func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) MarkReady(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID     string `json:"pull_request_id"`
+		ReviewersRequired int    `json:"reviewers_required"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	pr, err := h.service.MarkReady(r.Context(), req.PullRequestID, req.ReviewersRequired)
+	h.writeTransitionResult(w, r, pr, err)
+}
+
+func (h *Handler) ClosePR(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	pr, err := h.service.ClosePR(r.Context(), req.PullRequestID)
+	h.writeTransitionResult(w, r, pr, err)
+}
+
+func (h *Handler) ReopenPR(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	pr, err := h.service.ReopenPR(r.Context(), req.PullRequestID)
+	h.writeTransitionResult(w, r, pr, err)
+}
+
+// writeTransitionResult writes the response shared by the PR lifecycle
+// endpoints, including staffing warnings for a PR that became OPEN.
+func (h *Handler) writeTransitionResult(w http.ResponseWriter, r *http.Request, pr *model.PullRequest, err error) {
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "PR not found")
+			return
+		}
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid reviewers_required"))
+			return
+		}
+		if err.Error() == model.ErrPRMerged {
+			writeError(w, http.StatusConflict, model.ErrPRMerged, "PR is already merged")
+			return
+		}
+		if err.Error() == model.ErrInvalidTransition {
+			writeError(w, http.StatusConflict, model.ErrInvalidTransition, errorMessage(err, "invalid status transition"))
+			return
+		}
+		if err.Error() == model.ErrNoCandidate {
+			writeError(w, http.StatusConflict, model.ErrNoCandidate, errorMessage(err, "no reviewer with free capacity"))
+			return
+		}
+		if err.Error() == model.ErrUnderstaffed {
+			writeError(w, http.StatusConflict, model.ErrUnderstaffed, errorMessage(err, "not enough reviewers available"))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	warnings, err := h.service.StaffingWarnings(r.Context(), pr)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pr":       pr,
+		"warnings": warnings,
+	})
+}
+
+func (h *Handler) ListUnderReviewedPRs(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+
+	prs, err := h.service.ListUnderReviewedPRs(r.Context(), teamName)
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pull_requests": prs,
+	})
+}
+
+func (h *Handler) ListMergeOverrides(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+
+	overrides, err := h.service.ListMergeOverrides(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"merge_overrides": overrides,
+	})
+}
+
 func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id"`
 		OldUserID     string `json:"old_user_id"`
+		ActorID       string `json:"actor_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
 		return
 	}
 
-	pr, replacedBy, err := h.service.ReassignReviewer(req.PullRequestID, req.OldUserID)
+	pr, replacedBy, err := h.service.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, req.ActorID)
 	if err != nil {
 		if err.Error() == model.ErrNotFound {
 			writeError(w, http.StatusNotFound, model.ErrNotFound, "PR or user not found")
@@ -172,12 +476,16 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusConflict, model.ErrPRMerged, "cannot reassign on merged PR")
 			return
 		}
+		if err.Error() == model.ErrPRNotOpen {
+			writeError(w, http.StatusConflict, model.ErrPRNotOpen, errorMessage(err, "PR is not open"))
+			return
+		}
 		if err.Error() == model.ErrNotAssigned {
 			writeError(w, http.StatusConflict, model.ErrNotAssigned, "reviewer is not assigned to this PR")
 			return
 		}
 		if err.Error() == model.ErrNoCandidate {
-			writeError(w, http.StatusConflict, model.ErrNoCandidate, "no active replacement candidate in team")
+			writeError(w, http.StatusConflict, model.ErrNoCandidate, errorMessage(err, "no active replacement candidate in team"))
 			return
 		}
 		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
@@ -190,6 +498,73 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string  `json:"pull_request_id"`
+		UserID        string  `json:"user_id"`
+		Verdict       string  `json:"verdict"`
+		Comment       *string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	pr, review, err := h.service.SubmitReview(r.Context(), req.PullRequestID, req.UserID, req.Verdict, req.Comment)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid verdict"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "PR not found")
+			return
+		}
+		if err.Error() == model.ErrPRMerged {
+			writeError(w, http.StatusConflict, model.ErrPRMerged, "cannot review merged PR")
+			return
+		}
+		if err.Error() == model.ErrPRNotOpen {
+			writeError(w, http.StatusConflict, model.ErrPRNotOpen, errorMessage(err, "PR is not open"))
+			return
+		}
+		if err.Error() == model.ErrNotAssigned {
+			writeError(w, http.StatusConflict, model.ErrNotAssigned, "reviewer is not assigned to this PR")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pr":     pr,
+		"review": review,
+	})
+}
+
+func (h *Handler) GetPRHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "pull_request_id query parameter is required")
+		return
+	}
+
+	history, err := h.service.GetPRHistory(r.Context(), prID)
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "PR not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pull_request_id": prID,
+		"history":         history,
+	})
+}
+
 func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
@@ -197,7 +572,7 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prs, err := h.service.GetUserReviews(userID)
+	prs, err := h.service.GetUserReviews(r.Context(), userID)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
 		return
@@ -210,8 +585,30 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetStatistics(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.service.GetStatistics()
+	query := r.URL.Query()
+	opts := service.StatisticsOptions{
+		TeamName: query.Get("team_name"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+	}
+	if query.Get("limit") != "" {
+		limit, ok := parseLimit(w, r)
+		if !ok {
+			return
+		}
+		opts.Limit = limit
+	}
+
+	stats, err := h.service.GetStatistics(r.Context(), opts)
 	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid statistics query"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
 		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
 		return
 	}
@@ -219,16 +616,37 @@ func (h *Handler) GetStatistics(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, stats)
 }
 
+func (h *Handler) GetFairnessReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	report, err := h.service.GetFairnessReport(r.Context(), query.Get("team_name"), query.Get("from"), query.Get("to"))
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid fairness query"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
 func (h *Handler) DeactivateTeam(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		TeamName string `json:"team_name"`
+		ActorID  string `json:"actor_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
 		return
 	}
 
-	result, err := h.service.DeactivateTeam(req.TeamName)
+	result, err := h.service.DeactivateTeam(r.Context(), req.TeamName, req.ActorID)
 	if err != nil {
 		if err.Error() == model.ErrNotFound {
 			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (h *Handler) CreateReviewerPool(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PoolName string   `json:"pool_name"`
+		Members  []string `json:"members"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	pool, err := h.service.CreateReviewerPool(r.Context(), req.PoolName, req.Members)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid reviewer pool"))
+			return
+		}
+		if err.Error() == model.ErrPoolExists {
+			writeError(w, http.StatusBadRequest, model.ErrPoolExists, "pool_name already exists")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"pool": pool,
+	})
+}
+
+func (h *Handler) SetReviewerPoolMembers(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PoolName string   `json:"pool_name"`
+		Members  []string `json:"members"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	pool, err := h.service.SetReviewerPoolMembers(r.Context(), req.PoolName, req.Members)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid pool members"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "reviewer pool not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pool": pool,
+	})
+}
+
+func (h *Handler) GetReviewerPool(w http.ResponseWriter, r *http.Request) {
+	poolName := r.URL.Query().Get("pool_name")
+	if poolName == "" {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "pool_name query parameter is required")
+		return
+	}
+
+	pool, err := h.service.GetReviewerPool(r.Context(), poolName)
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "reviewer pool not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pool)
+}
+
+func (h *Handler) ListReviewerPools(w http.ResponseWriter, r *http.Request) {
+	pools, err := h.service.ListReviewerPools(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pools": pools,
+	})
+}
+
+func (h *Handler) DeleteReviewerPool(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PoolName string `json:"pool_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	if err := h.service.DeleteReviewerPool(r.Context(), req.PoolName); err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "reviewer pool not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pool_name": req.PoolName,
+	})
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (h *Handler) ListSLABreaches(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+	teamName := r.URL.Query().Get("team_name")
+
+	breaches, err := h.service.ListSLABreaches(r.Context(), teamName, limit)
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "team not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"breaches": breaches,
+	})
+}
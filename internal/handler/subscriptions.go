@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"pr-reviewer-service/internal/model"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+func (h *Handler) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL        string   `json:"url"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"event_types"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	sub, err := h.service.CreateWebhookSubscription(r.Context(), model.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid subscription"))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"subscription": sub,
+	})
+}
+
+func (h *Handler) ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.ListWebhookSubscriptions(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"subscriptions": subs,
+	})
+}
+
+func (h *Handler) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	if err := h.service.DeleteWebhookSubscription(r.Context(), req.ID); err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "subscription not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id": req.ID,
+	})
+}
+
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var subscriptionID int64
+	if raw := query.Get("subscription_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, "subscription_id must be an integer")
+			return
+		}
+		subscriptionID = id
+	}
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveries(r.Context(), subscriptionID, query.Get("status"), limit)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid filter"))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+	})
+}
+
+func (h *Handler) ListWebhookDeliveryAttempts(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := strconv.ParseInt(r.URL.Query().Get("delivery_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrInvalidInput, "delivery_id query parameter is required")
+		return
+	}
+
+	attempts, err := h.service.ListWebhookDeliveryAttempts(r.Context(), deliveryID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"delivery_id": deliveryID,
+		"attempts":    attempts,
+	})
+}
+
+func (h *Handler) ListWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+
+	letters, err := h.service.ListWebhookDeadLetters(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"dead_letters": letters,
+	})
+}
+
+// parseLimit reads the optional "limit" query parameter. It writes a 400
+// response and returns false if the value is not a positive integer.
+func parseLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultListLimit, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		writeError(w, http.StatusBadRequest, model.ErrInvalidInput, "limit must be a positive integer")
+		return 0, false
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	return limit, true
+}
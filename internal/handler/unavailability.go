@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (h *Handler) CreateUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID    string `json:"user_id"`
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		Reason    string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	window, err := h.service.CreateUnavailability(r.Context(), req.UserID, req.StartDate, req.EndDate, req.Reason)
+	if err != nil {
+		if err.Error() == model.ErrInvalidInput {
+			writeError(w, http.StatusBadRequest, model.ErrInvalidInput, errorMessage(err, "invalid unavailability window"))
+			return
+		}
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "user not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"unavailability": window,
+	})
+}
+
+func (h *Handler) ListUnavailability(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "user_id query parameter is required")
+		return
+	}
+
+	windows, err := h.service.ListUnavailability(r.Context(), userID)
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "user not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id":        userID,
+		"unavailability": windows,
+	})
+}
+
+func (h *Handler) DeleteUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrNotFound, "invalid request body")
+		return
+	}
+
+	if err := h.service.DeleteUnavailability(r.Context(), req.ID); err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusNotFound, model.ErrNotFound, "unavailability window not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id": req.ID,
+	})
+}
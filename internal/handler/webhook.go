@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/webhook"
+)
+
+const maxWebhookBodyBytes = 5 << 20
+
+func (h *Handler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrInvalidInput, "failed to read request body")
+		return
+	}
+
+	if !webhook.VerifyGitHubSignature([]byte(h.config.GitHubWebhookSecret), body, r.Header.Get("X-Hub-Signature-256")) {
+		writeError(w, http.StatusUnauthorized, model.ErrUnauthorized, "invalid webhook signature")
+		return
+	}
+
+	event, err := webhook.ParseGitHubEvent(r.Header.Get("X-GitHub-Event"), body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrInvalidInput, err.Error())
+		return
+	}
+
+	h.applyPullRequestEvent(w, r, event)
+}
+
+func (h *Handler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	if !webhook.VerifyGitLabToken(h.config.GitLabWebhookToken, r.Header.Get("X-Gitlab-Token")) {
+		writeError(w, http.StatusUnauthorized, model.ErrUnauthorized, "invalid webhook token")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrInvalidInput, "failed to read request body")
+		return
+	}
+
+	event, err := webhook.ParseGitLabEvent(r.Header.Get("X-Gitlab-Event"), body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, model.ErrInvalidInput, err.Error())
+		return
+	}
+
+	h.applyPullRequestEvent(w, r, event)
+}
+
+func (h *Handler) applyPullRequestEvent(w http.ResponseWriter, r *http.Request, event *model.PullRequestEvent) {
+	if event == nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"result": model.EventResultIgnored,
+		})
+		return
+	}
+
+	result, pr, err := h.service.ApplyPullRequestEvent(r.Context(), *event)
+	if err != nil {
+		if err.Error() == model.ErrNotFound {
+			writeError(w, http.StatusUnprocessableEntity, model.ErrNotFound, errorMessage(err, "PR or author not found"))
+			return
+		}
+		if err.Error() == model.ErrPRExists {
+			writeError(w, http.StatusConflict, model.ErrPRExists, "PR id already exists")
+			return
+		}
+		if err.Error() == model.ErrNoCandidate {
+			writeError(w, http.StatusConflict, model.ErrNoCandidate, errorMessage(err, "no reviewer with free capacity"))
+			return
+		}
+		if err.Error() == model.ErrUnderstaffed {
+			writeError(w, http.StatusConflict, model.ErrUnderstaffed, errorMessage(err, "not enough reviewers available"))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, model.ErrNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"result": result,
+		"pr":     pr,
+	})
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/service"
+	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/webhook"
+)
+
+const (
+	testGitHubSecret = "test-secret"
+	testGitLabToken  = "test-token"
+)
+
+func newWebhookTestHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	svc := service.New(storage.NewMemory())
+	team := model.Team{
+		TeamName: "backend",
+		Members: []model.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+		},
+	}
+	if _, err := svc.CreateTeam(t.Context(), team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	if _, err := svc.LinkExternalAccount(t.Context(), model.ExternalAccount{
+		Provider: model.ProviderGitHub, Login: "alice-dev", UserID: "u1",
+	}); err != nil {
+		t.Fatalf("Failed to link account: %v", err)
+	}
+	if _, err := svc.LinkExternalAccount(t.Context(), model.ExternalAccount{
+		Provider: model.ProviderGitLab, Login: "alice.dev", UserID: "u1",
+	}); err != nil {
+		t.Fatalf("Failed to link account: %v", err)
+	}
+
+	return New(svc, Config{GitHubWebhookSecret: testGitHubSecret, GitLabWebhookToken: testGitLabToken})
+}
+
+func deliverGitHub(t *testing.T, h *Handler, event, fixture string, sign bool) (int, map[string]interface{}) {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("..", "webhook", "testdata", fixture))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	if sign {
+		req.Header.Set("X-Hub-Signature-256", webhook.Sign([]byte(testGitHubSecret), body))
+	}
+	rec := httptest.NewRecorder()
+	h.GitHubWebhook(rec, req)
+
+	var result map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&result)
+	return rec.Code, result
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	h := newWebhookTestHandler(t)
+
+	t.Run("RejectsUnsigned", func(t *testing.T) {
+		status, _ := deliverGitHub(t, h, "pull_request", "github_pull_request_opened.json", false)
+		if status != http.StatusUnauthorized {
+			t.Fatalf("Expected status 401, got %d", status)
+		}
+	})
+
+	t.Run("Opened", func(t *testing.T) {
+		status, result := deliverGitHub(t, h, "pull_request", "github_pull_request_opened.json", true)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %v", status, result)
+		}
+		if result["result"] != model.EventResultCreated {
+			t.Errorf("Expected result created, got %v", result["result"])
+		}
+		pr := result["pr"].(map[string]interface{})
+		if pr["author_id"] != "u1" {
+			t.Errorf("Expected author u1, got %v", pr["author_id"])
+		}
+		if len(pr["assigned_reviewers"].([]interface{})) != 2 {
+			t.Errorf("Expected 2 reviewers, got %v", pr["assigned_reviewers"])
+		}
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		status, result := deliverGitHub(t, h, "pull_request", "github_pull_request_merged.json", true)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %v", status, result)
+		}
+		pr := result["pr"].(map[string]interface{})
+		if pr["status"] != model.StatusMerged {
+			t.Errorf("Expected status MERGED, got %v", pr["status"])
+		}
+	})
+
+	t.Run("Ping", func(t *testing.T) {
+		status, result := deliverGitHub(t, h, "ping", "github_ping.json", true)
+		if status != http.StatusOK || result["result"] != model.EventResultIgnored {
+			t.Errorf("Expected ping to be ignored, got %d: %v", status, result)
+		}
+	})
+}
+
+func deliverGitLab(t *testing.T, h *Handler, fixture, token string) (int, map[string]interface{}) {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("..", "webhook", "testdata", fixture))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", bytes.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", token)
+	rec := httptest.NewRecorder()
+	h.GitLabWebhook(rec, req)
+
+	var result map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&result)
+	return rec.Code, result
+}
+
+func TestGitLabWebhook(t *testing.T) {
+	h := newWebhookTestHandler(t)
+
+	t.Run("RejectsWrongToken", func(t *testing.T) {
+		status, _ := deliverGitLab(t, h, "gitlab_merge_request_open.json", "wrong")
+		if status != http.StatusUnauthorized {
+			t.Fatalf("Expected status 401, got %d", status)
+		}
+	})
+
+	t.Run("UpdateOfUnknownMR", func(t *testing.T) {
+		// The update was pushed by alice.dev, who need not be the author, so
+		// the MR must not be created with her as author.
+		status, result := deliverGitLab(t, h, "gitlab_merge_request_update.json", testGitLabToken)
+		if status != http.StatusOK || result["result"] != model.EventResultIgnored || result["pr"] != nil {
+			t.Fatalf("Expected update of an unknown MR to be ignored, got %d: %v", status, result)
+		}
+		if reviews, _ := h.service.GetUserReviews(t.Context(), "u2"); len(reviews) != 0 {
+			t.Errorf("Expected no PR to be created, got %v", reviews)
+		}
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		status, result := deliverGitLab(t, h, "gitlab_merge_request_open.json", testGitLabToken)
+		if status != http.StatusOK || result["result"] != model.EventResultCreated {
+			t.Fatalf("Expected PR to be created, got %d: %v", status, result)
+		}
+	})
+
+	t.Run("OpenRedelivered", func(t *testing.T) {
+		status, result := deliverGitLab(t, h, "gitlab_merge_request_open.json", testGitLabToken)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200 on redelivery, got %d: %v", status, result)
+		}
+		if result["result"] != model.EventResultIgnored {
+			t.Errorf("Expected result ignored, got %v", result["result"])
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		status, result := deliverGitLab(t, h, "gitlab_merge_request_update.json", testGitLabToken)
+		if status != http.StatusOK || result["result"] != model.EventResultIgnored {
+			t.Errorf("Expected update of known MR to be ignored, got %d: %v", status, result)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			status, result := deliverGitLab(t, h, "gitlab_merge_request_merge.json", testGitLabToken)
+			if status != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %v", status, result)
+			}
+			pr := result["pr"].(map[string]interface{})
+			if pr["status"] != model.StatusMerged {
+				t.Errorf("Expected status MERGED, got %v", pr["status"])
+			}
+		}
+	})
+}
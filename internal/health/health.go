@@ -0,0 +1,194 @@
+// Package health serves the liveness and readiness probes. Liveness only
+// says that the process serves HTTP; readiness says whether it should get
+// traffic, which depends on the startup phase and on registered checks.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Phases of the process lifecycle. Only PhaseReady can be ready.
+const (
+	PhaseStarting     = "starting"
+	PhaseMigrating    = "migrating"
+	PhaseReady        = "ready"
+	PhaseShuttingDown = "shutting_down"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// checkTimeout bounds each readiness check, so that a hanging database does
+// not hang the probe.
+const checkTimeout = 2 * time.Second
+
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+type Checker struct {
+	mu     sync.RWMutex
+	phase  string
+	checks []check
+	now    func() time.Time
+}
+
+func NewChecker() *Checker {
+	return &Checker{phase: PhaseStarting, now: time.Now}
+}
+
+func (c *Checker) SetPhase(phase string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.phase = phase
+}
+
+func (c *Checker) Phase() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.phase
+}
+
+// AddCheck registers a readiness check. Checks run in registration order.
+func (c *Checker) AddCheck(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Heartbeat registers a readiness check for a background worker that runs
+// every interval and returns the heartbeat the worker reports to.
+func (c *Checker) Heartbeat(name string, interval time.Duration) *Heartbeat {
+	hb := newHeartbeat(interval, c.now)
+	c.AddCheck("worker:"+name, hb.check)
+	return hb
+}
+
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+type Report struct {
+	Status string        `json:"status"`
+	Phase  string        `json:"phase"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Check runs the registered checks. Outside PhaseReady no check is run and
+// the report fails, because the process is not fully set up or is going
+// away.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	phase, checks := c.phase, c.checks
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Phase: phase, Checks: []CheckResult{}}
+	if phase != PhaseReady {
+		report.Status = StatusFail
+		return report
+	}
+
+	for _, ch := range checks {
+		ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		start := c.now()
+		err := ch.fn(ctx)
+		cancel()
+
+		result := CheckResult{
+			Name:       ch.name,
+			Status:     StatusOK,
+			DurationMs: float64(c.now().Sub(start).Microseconds()) / 1000,
+		}
+		if err != nil {
+			result.Status = StatusFail
+			result.Error = err.Error()
+			report.Status = StatusFail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// Liveness answers 200 as long as the process can serve HTTP at all.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// Readiness answers 200 with the check report when the process is ready
+// and 503 with the same report otherwise.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// Heartbeat tracks the iterations of a background worker. The worker is
+// unhealthy once it has gone too long without a successful iteration,
+// whether it is stuck or keeps failing; a single failed run, which the next
+// one may fix, does not make the process unready. A worker that has not
+// finished any run yet gets the same grace period from registration.
+type Heartbeat struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+	lastErr     error
+}
+
+func newHeartbeat(interval time.Duration, now func() time.Time) *Heartbeat {
+	// One iteration may legitimately take long (a batch of slow webhook
+	// deliveries), so the worker is only considered unhealthy after missing
+	// several runs.
+	return &Heartbeat{maxAge: 3*interval + time.Minute, now: now, lastSuccess: now()}
+}
+
+// Beat records the outcome of one iteration. It is a no-op on a nil
+// Heartbeat, so workers can call it unconditionally.
+func (h *Heartbeat) Beat(err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastErr = err
+	if err == nil {
+		h.lastSuccess = h.now()
+	}
+}
+
+func (h *Heartbeat) check(context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	age := h.now().Sub(h.lastSuccess)
+	if age <= h.maxAge {
+		return nil
+	}
+	if h.lastErr != nil {
+		return fmt.Errorf("no successful iteration for %s: %w", age.Round(time.Second), h.lastErr)
+	}
+	return fmt.Errorf("no iteration for %s", age.Round(time.Second))
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func readiness(t *testing.T, c *Checker) (int, Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("Invalid readiness body: %v", err)
+	}
+	return rec.Code, report
+}
+
+func TestReadinessFollowsPhase(t *testing.T) {
+	c := NewChecker()
+	c.AddCheck("database", func(context.Context) error { return nil })
+
+	for _, phase := range []string{PhaseStarting, PhaseMigrating, PhaseShuttingDown} {
+		c.SetPhase(phase)
+		code, report := readiness(t, c)
+		if code != http.StatusServiceUnavailable || report.Phase != phase || report.Status != StatusFail {
+			t.Errorf("Expected 503 in phase %s, got %d %+v", phase, code, report)
+		}
+	}
+
+	c.SetPhase(PhaseReady)
+	code, report := readiness(t, c)
+	if code != http.StatusOK || report.Status != StatusOK {
+		t.Fatalf("Expected 200 when ready, got %d %+v", code, report)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Name != "database" || report.Checks[0].Status != StatusOK {
+		t.Errorf("Expected the database check to be reported, got %+v", report.Checks)
+	}
+}
+
+func TestReadinessReportsFailingCheck(t *testing.T) {
+	c := NewChecker()
+	c.AddCheck("database", func(context.Context) error { return errors.New("connection refused") })
+	c.AddCheck("migrations", func(context.Context) error { return nil })
+	c.SetPhase(PhaseReady)
+
+	code, report := readiness(t, c)
+	if code != http.StatusServiceUnavailable || report.Status != StatusFail {
+		t.Fatalf("Expected 503, got %d %+v", code, report)
+	}
+	if report.Checks[0].Status != StatusFail || report.Checks[0].Error != "connection refused" {
+		t.Errorf("Expected the database failure to be reported, got %+v", report.Checks[0])
+	}
+	if report.Checks[1].Status != StatusOK {
+		t.Errorf("Expected the other checks to still run, got %+v", report.Checks[1])
+	}
+}
+
+func TestLivenessIgnoresPhase(t *testing.T) {
+	c := NewChecker()
+	rec := httptest.NewRecorder()
+	c.Liveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 while starting, got %d", rec.Code)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	now := time.Now()
+	c := NewChecker()
+	c.now = func() time.Time { return now }
+	hb := c.Heartbeat("relay", time.Second)
+	maxAge := 3*time.Second + time.Minute
+
+	if err := hb.check(context.Background()); err != nil {
+		t.Errorf("Expected a new worker to get a grace period, got %v", err)
+	}
+
+	now = now.Add(maxAge / 2)
+	hb.Beat(errors.New("db down"))
+	if err := hb.check(context.Background()); err != nil {
+		t.Errorf("Expected a single failure to be tolerated, got %v", err)
+	}
+
+	now = now.Add(maxAge)
+	if err := hb.check(context.Background()); err == nil {
+		t.Error("Expected a worker failing for too long to be unhealthy")
+	}
+
+	hb.Beat(nil)
+	if err := hb.check(context.Background()); err != nil {
+		t.Errorf("Expected a successful run to restore health, got %v", err)
+	}
+
+	now = now.Add(2 * maxAge)
+	if err := hb.check(context.Background()); err == nil {
+		t.Error("Expected a stuck worker to be unhealthy")
+	}
+
+	var none *Heartbeat
+	none.Beat(nil)
+}
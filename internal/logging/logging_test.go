@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// captureLogs routes the default logger into a buffer for the duration of
+// the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	prev := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func newTestRouter() *mux.Router {
+	r := mux.NewRouter()
+	r.Use(Middleware)
+	r.HandleFunc("/pullRequest/create", func(w http.ResponseWriter, r *http.Request) {
+		slog.InfoContext(r.Context(), "Pull request created")
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST")
+	return r
+}
+
+func TestMiddlewarePropagatesRequestID(t *testing.T) {
+	buf := captureLogs(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "req-42" {
+		t.Errorf("Expected response %s req-42, got %q", RequestIDHeader, got)
+	}
+
+	lines := logLines(t, buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected a handler line and an access line, got %v", lines)
+	}
+	for _, line := range lines {
+		if line["request_id"] != "req-42" {
+			t.Errorf("Expected request_id req-42 in %v", line)
+		}
+	}
+	access := lines[1]
+	if access["method"] != "POST" || access["route"] != "/pullRequest/create" || access["status"] != float64(http.StatusCreated) {
+		t.Errorf("Unexpected access line %v", access)
+	}
+	if _, ok := access["latency_ms"]; !ok {
+		t.Errorf("Expected latency_ms in %v", access)
+	}
+}
+
+func TestMiddlewareAssignsRequestID(t *testing.T) {
+	for _, incoming := range []string{"", "bad id\nwith newline", strings.Repeat("x", maxRequestIDLength+1)} {
+		buf := captureLogs(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+		req.Header.Set(RequestIDHeader, incoming)
+		rec := httptest.NewRecorder()
+		newTestRouter().ServeHTTP(rec, req)
+
+		id := rec.Header().Get(RequestIDHeader)
+		if id == "" || id == incoming {
+			t.Errorf("Expected a new request ID for %q, got %q", incoming, id)
+		}
+		for _, line := range logLines(t, buf) {
+			if line["request_id"] != id {
+				t.Errorf("Expected request_id %s in %v", id, line)
+			}
+		}
+	}
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "loud", FormatJSON); err == nil {
+		t.Error("Expected an unknown level to be rejected")
+	}
+	if _, err := New(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+	if _, err := New(&bytes.Buffer{}, "DEBUG", "Text"); err != nil {
+		t.Errorf("Expected level and format to be case-insensitive: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package metrics holds the Prometheus collectors of the service and serves
+// them in the text exposition format on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry is where every collector of the service is registered. It is
+// separate from the prometheus default registry so that tests and other
+// importers do not share state with it by accident.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route template.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	dbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of storage operations against PostgreSQL.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	assignments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reviewer_assignments_total",
+		Help: "Reviewer assignments by action (assigned, reassigned) and reason.",
+	}, []string{"action", "reason"})
+
+	noCandidate = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reviewer_no_candidate_total",
+		Help: "Times no reviewer could be found, by team.",
+	}, []string{"team"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, dbDuration, assignments, noCandidate,
+	)
+}
+
+// Handler serves the registered metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveQuery records that the storage operation took d.
+func ObserveQuery(operation string, d time.Duration) {
+	dbDuration.WithLabelValues(operation).Observe(d.Seconds())
+}
+
+// Assigned counts n reviewer assignments. action and reason take the values
+// of the reviewer history, model.Assignment* and model.Reason*.
+func Assigned(action, reason string, n int) {
+	if n > 0 {
+		assignments.WithLabelValues(action, reason).Add(float64(n))
+	}
+}
+
+// NoCandidate counts a failure to find a reviewer in team.
+func NoCandidate(team string) {
+	noCandidate.WithLabelValues(team).Inc()
+}
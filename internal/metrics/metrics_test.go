@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMiddlewareLabelsRouteTemplate(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(Middleware)
+	r.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}).Methods("GET")
+
+	for _, path := range []string{"/items/1", "/items/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t)
+	want := `http_requests_total{method="GET",route="/items/{id}",status="418"} 2`
+	if !strings.Contains(body, want) {
+		t.Errorf("Expected %q in:\n%s", want, body)
+	}
+	if !strings.Contains(body, `http_request_duration_seconds_count{method="GET",route="/items/{id}"} 2`) {
+		t.Errorf("Expected latency histogram for the route")
+	}
+}
+
+func TestTeamLoadAndCounters(t *testing.T) {
+	RegisterTeamLoad(func(context.Context) ([]model.TeamLoad, error) {
+		return []model.TeamLoad{{TeamName: "backend", OpenPRs: 3, OpenReviews: 5}}, nil
+	})
+	Assigned(model.AssignmentReassigned, model.ReasonOOO, 1)
+	Assigned(model.AssignmentAssigned, model.ReasonAuto, 0)
+	NoCandidate("backend")
+
+	body := scrape(t)
+	for _, want := range []string{
+		`team_open_prs{team="backend"} 3`,
+		`team_open_reviews{team="backend"} 5`,
+		`reviewer_assignments_total{action="reassigned",reason="ooo"} 1`,
+		`reviewer_no_candidate_total{team="backend"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in metrics output", want)
+		}
+	}
+	if strings.Contains(body, `reason="auto"`) {
+		t.Errorf("Expected no series for zero assignments")
+	}
+}
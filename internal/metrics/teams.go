@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"pr-reviewer-service/internal/model"
+)
+
+var (
+	openPRsDesc = prometheus.NewDesc("team_open_prs",
+		"OPEN pull requests by the team of their author.", []string{"team"}, nil)
+	openReviewsDesc = prometheus.NewDesc("team_open_reviews",
+		"Reviewer assignments on OPEN pull requests by the team of the reviewer.", []string{"team"}, nil)
+)
+
+// teamCollector reads the per-team gauges from storage on every scrape, so
+// they are exact across several replicas.
+type teamCollector struct {
+	load func(context.Context) ([]model.TeamLoad, error)
+}
+
+// RegisterTeamLoad registers the team_open_prs and team_open_reviews gauges,
+// computed by load on each scrape.
+func RegisterTeamLoad(load func(context.Context) ([]model.TeamLoad, error)) {
+	Registry.MustRegister(&teamCollector{load: load})
+}
+
+func (c *teamCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- openPRsDesc
+	ch <- openReviewsDesc
+}
+
+func (c *teamCollector) Collect(ch chan<- prometheus.Metric) {
+	teams, err := c.load(context.Background())
+	if err != nil {
+		slog.Error("Failed to collect team load metrics", "err", err)
+		return
+	}
+	for _, t := range teams {
+		ch <- prometheus.MustNewConstMetric(openPRsDesc, prometheus.GaugeValue, float64(t.OpenPRs), t.TeamName)
+		ch <- prometheus.MustNewConstMetric(openReviewsDesc, prometheus.GaugeValue, float64(t.OpenReviews), t.TeamName)
+	}
+}
@@ -0,0 +1,357 @@
+// Package migrate applies the versioned SQL migrations in migrations/ and
+// records them in the schema_migrations table.
+//
+// A migration is a pair of files NNN_name.up.sql and NNN_name.down.sql,
+// where NNN is its version; the down file is optional. Every migration runs
+// in its own transaction together with its schema_migrations row, so a
+// failed migration leaves no trace. The checksum of each applied up file is
+// stored and verified on every run, which catches edits to migrations that
+// have already shipped. A Postgres advisory lock serializes migrators, so
+// several replicas can start at once.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockID identifies the advisory lock held while migrating. Any constant
+// works as long as nothing else in the database uses it.
+const lockID int64 = 0x70725f726576 // "pr_rev"
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Load reads the migrations in the root of fsys, ordered by version.
+// Files that do not look like migrations are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Status describes one migration known to the files, the database or both.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	// Modified is set when the up file changed after it was applied.
+	Modified bool
+	// Missing is set when the migration is applied but has no file.
+	Missing bool
+}
+
+type applied struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Latest returns the version of the newest migration, or 0 if there are
+// none.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations in version order and returns them.
+// It refuses to run if an applied migration was modified.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		state, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(state); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := state[mig.Version]; ok {
+				continue
+			}
+			slog.InfoContext(ctx, "Applying migration", "version", mig.Version, "name", mig.Name)
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `
+					INSERT INTO schema_migrations (version, name, checksum, applied_at)
+					VALUES ($1, $2, $3, $4)`,
+					mig.Version, mig.Name, mig.Checksum, time.Now())
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the steps most recently applied migrations, newest first,
+// and returns them. Each of them must have a down file.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		state, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(state); err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(state))
+		for v := range state {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		if steps < len(versions) {
+			versions = versions[:steps]
+		}
+
+		for _, v := range versions {
+			mig := m.find(v)
+			if mig == nil {
+				return fmt.Errorf("migration %d_%s is applied but its files are missing", v, state[v].name)
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+			}
+			slog.InfoContext(ctx, "Rolling back migration", "version", mig.Version, "name", mig.Name)
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("roll back migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, *mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every migration from the files and the database in version
+// order. It neither takes the lock nor creates schema_migrations, so it is
+// cheap enough for readiness probes.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var table sql.NullString
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations')::text`).Scan(&table); err != nil {
+		return nil, err
+	}
+	state := map[int64]applied{}
+	if table.Valid {
+		var err error
+		if state, err = loadApplied(ctx, m.db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := []Status{}
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if a, ok := state[mig.Version]; ok {
+			appliedAt := a.appliedAt
+			s.AppliedAt = &appliedAt
+			s.Modified = a.checksum != mig.Checksum
+		}
+		statuses = append(statuses, s)
+	}
+	for _, a := range state {
+		if m.find(a.version) == nil {
+			appliedAt := a.appliedAt
+			statuses = append(statuses, Status{Version: a.version, Name: a.name, AppliedAt: &appliedAt, Missing: true})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Check fails if a migration is pending or an applied one was modified.
+// Applied migrations without files are fine: they come from a newer
+// release that is being rolled out next to this one.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, s := range statuses {
+		switch {
+		case s.Modified:
+			errs = append(errs, fmt.Errorf("migration %d_%s was modified after it was applied", s.Version, s.Name))
+		case s.AppliedAt == nil:
+			errs = append(errs, fmt.Errorf("migration %d_%s is pending", s.Version, s.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) verify(state map[int64]applied) error {
+	var errs []error
+	for _, mig := range m.migrations {
+		if a, ok := state[mig.Version]; ok && a.checksum != mig.Checksum {
+			errs = append(errs, fmt.Errorf("migration %d_%s was modified after it was applied", mig.Version, mig.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// locked runs fn on a single connection that holds the migration advisory
+// lock. The lock is session-level, so it has to be taken and released on
+// the same connection.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)`)
+	return err
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func loadApplied(ctx context.Context, q querier) (map[int64]applied, error) {
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	state := map[int64]applied{}
+	for rows.Next() {
+		var a applied
+		if err := rows.Scan(&a.version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		state[a.version] = a
+	}
+	return state, rows.Err()
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
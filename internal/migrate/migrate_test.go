@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadOrdersAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_pools.up.sql":   {Data: []byte("CREATE TABLE pools ();")},
+		"002_users.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+		"002_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"001_init.up.sql":    {Data: []byte("CREATE TABLE teams ();")},
+		"README.md":          {Data: []byte("not a migration")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("Expected 3 migrations, got %d", len(migrations))
+	}
+	for i, want := range []int64{1, 2, 10} {
+		if migrations[i].Version != want {
+			t.Errorf("Expected version %d at %d, got %d", want, i, migrations[i].Version)
+		}
+	}
+	users := migrations[1]
+	if users.Name != "users" || users.Up != "CREATE TABLE users ();" || users.Down != "DROP TABLE users;" {
+		t.Errorf("Unexpected migration %+v", users)
+	}
+	if migrations[0].Down != "" {
+		t.Errorf("Expected no down migration for 001, got %q", migrations[0].Down)
+	}
+	if len(users.Checksum) != 64 || users.Checksum == migrations[0].Checksum {
+		t.Errorf("Expected distinct sha256 checksums, got %q and %q", users.Checksum, migrations[0].Checksum)
+	}
+}
+
+func TestLoadChecksumIgnoresDownFile(t *testing.T) {
+	a, err := Load(fstest.MapFS{"001_init.up.sql": {Data: []byte("SELECT 1;")}})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	b, err := Load(fstest.MapFS{
+		"001_init.up.sql":   {Data: []byte("SELECT 1;")},
+		"001_init.down.sql": {Data: []byte("SELECT 2;")},
+	})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if a[0].Checksum != b[0].Checksum {
+		t.Errorf("Expected adding a down file to keep the checksum")
+	}
+}
+
+func TestLoadRejectsInvalidSets(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"001_init.up.sql":  {Data: []byte("SELECT 1;")},
+				"001_other.up.sql": {Data: []byte("SELECT 2;")},
+			},
+			want: "used by both",
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{"002_users.down.sql": {Data: []byte("DROP TABLE users;")}},
+			want: "no up file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadShippedMigrations(t *testing.T) {
+	migrations, err := Load(os.DirFS("../../migrations"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Expected shipped migrations")
+	}
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("Expected version %d, got %d_%s", i+1, m.Version, m.Name)
+		}
+		if m.Down == "" {
+			t.Errorf("Migration %d_%s has no down file", m.Version, m.Name)
+		}
+	}
+}
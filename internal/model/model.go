@@ -1,12 +1,18 @@
 package model
 
-import "time"
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
 
 type User struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	// MaxOpenReviews overrides the team's max_open_reviews when set.
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
 }
 
 type TeamMember struct {
@@ -15,19 +21,282 @@ type TeamMember struct {
 	IsActive bool   `json:"is_active"`
 }
 
+type TeamSettings struct {
+	ReviewerStrategy  string `json:"reviewer_strategy"`
+	ReviewersRequired int    `json:"reviewers_required"`
+	MaxReviewers      int    `json:"max_reviewers"`
+	// ApprovalsRequired is the number of assigned reviewers that must approve
+	// before a PR can be merged. Zero disables merge gating.
+	ApprovalsRequired int `json:"approvals_required"`
+	// MaxOpenReviews caps the open reviews of each member who has no
+	// personal limit. Zero means unlimited.
+	MaxOpenReviews int `json:"max_open_reviews"`
+	// CapacityOverflow decides what happens when every candidate is at
+	// capacity: CapacityReject or CapacityLeastLoaded.
+	CapacityOverflow string `json:"capacity_overflow"`
+	// CodeOwnersMode decides how owners of the changed files are treated
+	// when picking reviewers: CodeOwnersPrefer or CodeOwnersRequire.
+	CodeOwnersMode string `json:"code_owners_mode"`
+	// FallbackPools are reviewer pools, tried in order, that fill the
+	// reviewer slots the team's own members cannot.
+	FallbackPools []string `json:"fallback_pools"`
+	// UnderstaffedPolicy decides what happens when fewer reviewers than
+	// required can be assigned: UnderstaffedAllow, UnderstaffedWarn or
+	// UnderstaffedReject.
+	UnderstaffedPolicy string `json:"understaffed_policy"`
+	// ReviewSLAMinutes is how long an assigned reviewer may stay without a
+	// verdict before the assignment breaches the SLA. Zero disables it.
+	ReviewSLAMinutes int `json:"review_sla_minutes"`
+	// SLAAction is what happens on a breach: SLAEscalate or SLAReassign.
+	SLAAction string `json:"sla_action"`
+	// TeamLeadID is the user escalations are addressed to, if any.
+	TeamLeadID string `json:"team_lead_id"`
+}
+
+// TeamSettingsUpdate is a partial update of TeamSettings; nil fields are left unchanged.
+type TeamSettingsUpdate struct {
+	ReviewerStrategy   *string   `json:"reviewer_strategy"`
+	ReviewersRequired  *int      `json:"reviewers_required"`
+	MaxReviewers       *int      `json:"max_reviewers"`
+	ApprovalsRequired  *int      `json:"approvals_required"`
+	MaxOpenReviews     *int      `json:"max_open_reviews"`
+	CapacityOverflow   *string   `json:"capacity_overflow"`
+	CodeOwnersMode     *string   `json:"code_owners_mode"`
+	FallbackPools      *[]string `json:"fallback_pools"`
+	UnderstaffedPolicy *string   `json:"understaffed_policy"`
+	ReviewSLAMinutes   *int      `json:"review_sla_minutes"`
+	SLAAction          *string   `json:"sla_action"`
+	TeamLeadID         *string   `json:"team_lead_id"`
+}
+
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName string `json:"team_name"`
+	TeamSettings
+	Members []TeamMember `json:"members"`
 }
 
 type PullRequest struct {
+	PullRequestID     string           `json:"pull_request_id"`
+	PullRequestName   string           `json:"pull_request_name"`
+	AuthorID          string           `json:"author_id"`
+	Status            string           `json:"status"`
+	AssignedReviewers []string         `json:"assigned_reviewers"`
+	ReviewerSources   []ReviewerSource `json:"reviewer_sources"`
+	// ReviewersRequired is the reviewer count resolved when reviewers were
+	// first assigned; zero for a draft.
+	ReviewersRequired int             `json:"reviewers_required"`
+	Reviews           []ReviewVerdict `json:"reviews"`
+	CreatedAt         *string         `json:"createdAt,omitempty"`
+	MergedAt          *string         `json:"mergedAt,omitempty"`
+	ClosedAt          *string         `json:"closedAt,omitempty"`
+	ChangedFiles      []string        `json:"changed_files,omitempty"`
+}
+
+// ReviewerSource tells where an assigned reviewer was drawn from: the
+// author's team, the owners of the changed files or a reviewer pool.
+type ReviewerSource struct {
+	UserID string `json:"user_id"`
+	Source string `json:"source"`
+	// Name is the team or pool the reviewer was picked from.
+	Name string `json:"name"`
+}
+
+// ReviewerPool is a named group of users that may span teams. Teams use
+// pools as fallbacks when their own members cannot review.
+type ReviewerPool struct {
+	PoolName  string   `json:"pool_name"`
+	Members   []string `json:"members"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// CodeOwners holds a team's ownership rules as uploaded, in the GitHub
+// CODEOWNERS format.
+type CodeOwners struct {
+	TeamName  string `json:"team_name"`
+	Content   string `json:"content"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Unavailability is a dated out-of-office window during which the user is
+// not picked as a reviewer. Both dates are inclusive and formatted as
+// DateLayout.
+type Unavailability struct {
+	ID        int64  `json:"id"`
+	UserID    string `json:"user_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"created_at"`
+	// ReviewsReassignedAt is set once the OOO job has handed the user's open
+	// reviews over for this window.
+	ReviewsReassignedAt *string `json:"reviews_reassigned_at,omitempty"`
+}
+
+// ReviewerAssignment is one entry of a PR's append-only reviewer history.
+// A reassignment is recorded as an unassigned entry for the old reviewer
+// followed by a reassigned entry for the new one.
+type ReviewerAssignment struct {
+	ID             int64   `json:"id"`
+	PullRequestID  string  `json:"pull_request_id"`
+	UserID         string  `json:"user_id"`
+	Action         string  `json:"action"`
+	Reason         string  `json:"reason"`
+	ActorID        *string `json:"actor_id"`
+	ReplacedUserID *string `json:"replaced_user_id,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// MergeOverride is the audit record of a forced merge that bypassed the
+// team's approval requirement.
+type MergeOverride struct {
+	ID                int64  `json:"id"`
+	PullRequestID     string `json:"pull_request_id"`
+	ForcedBy          string `json:"forced_by"`
+	Reason            string `json:"reason"`
+	Approvals         int    `json:"approvals"`
+	ApprovalsRequired int    `json:"approvals_required"`
+	CreatedAt         string `json:"created_at"`
+}
+
+// ReviewVerdict is the latest verdict of a currently assigned reviewer.
+type ReviewVerdict struct {
+	UserID      string `json:"user_id"`
+	Verdict     string `json:"verdict"`
+	SubmittedAt string `json:"submitted_at"`
+}
+
+type Review struct {
+	ID            int64   `json:"id"`
+	PullRequestID string  `json:"pull_request_id"`
+	UserID        string  `json:"user_id"`
+	Verdict       string  `json:"verdict"`
+	Comment       *string `json:"comment,omitempty"`
+	SubmittedAt   string  `json:"submitted_at"`
+}
+
+// OverdueAssignment is a reviewer assignment on an open PR that has had no
+// verdict for longer than the SLA of the PR author's team.
+type OverdueAssignment struct {
+	PullRequestID string
+	ReviewerID    string
+	TeamName      string
+	AssignedAt    time.Time
+	SLAMinutes    int
+}
+
+// SLABreach records how an overdue assignment was handled. Each assignment
+// is handled at most once.
+type SLABreach struct {
+	ID            int64   `json:"id"`
+	PullRequestID string  `json:"pull_request_id"`
+	ReviewerID    string  `json:"reviewer_id"`
+	TeamName      string  `json:"team_name"`
+	AssignedAt    string  `json:"assigned_at"`
+	SLAMinutes    int     `json:"sla_minutes"`
+	Action        string  `json:"action"`
+	NewReviewerID *string `json:"new_reviewer_id,omitempty"`
+	TeamLeadID    *string `json:"team_lead_id,omitempty"`
+	DetectedAt    string  `json:"detected_at"`
+}
+
+// StatisticsFilter narrows GET /statistics. An empty TeamName covers every
+// team; a nil From or To leaves that end of the window open. To is
+// exclusive.
+type StatisticsFilter struct {
+	TeamName string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+}
+
+// Statistics summarizes PRs and review load. PR counts cover PRs created in
+// the window, TimeToMerge PRs merged in it, and TopReviewers and
+// Reassignments assignments made in it. OpenReviewLoad is always the
+// current load.
+type Statistics struct {
+	TeamName       string           `json:"team_name,omitempty"`
+	From           *string          `json:"from,omitempty"`
+	To             *string          `json:"to,omitempty"`
+	TotalPRs       int              `json:"total_prs"`
+	DraftPRs       int              `json:"draft_prs"`
+	OpenPRs        int              `json:"open_prs"`
+	MergedPRs      int              `json:"merged_prs"`
+	ClosedPRs      int              `json:"closed_prs"`
+	TimeToMerge    TimeToMerge      `json:"time_to_merge"`
+	Reassignments  ReassignmentStat `json:"reassignments"`
+	TopReviewers   []ReviewerStat   `json:"top_reviewers"`
+	OpenReviewLoad []ReviewLoad     `json:"open_review_load"`
+}
+
+// TimeToMerge describes how long merged PRs took from created_at to
+// merged_at. The percentiles are nil when nothing was merged.
+type TimeToMerge struct {
+	Count         int      `json:"count"`
+	MedianSeconds *float64 `json:"median_seconds"`
+	P90Seconds    *float64 `json:"p90_seconds"`
+}
+
+type ReassignmentStat struct {
+	Total    int            `json:"total"`
+	ByReason map[string]int `json:"by_reason"`
+}
+
+type ReviewerStat struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	ReviewCount int    `json:"review_count"`
+}
+
+// ReviewLoad is the number of OPEN PRs a user currently reviews.
+type ReviewLoad struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	TeamName    string `json:"team_name"`
+	OpenReviews int    `json:"open_reviews"`
+}
+
+// TeamLoad is the current number of OPEN PRs authored in a team and of
+// reviews its members have on OPEN PRs.
+type TeamLoad struct {
+	TeamName    string
+	OpenPRs     int
+	OpenReviews int
+}
+
+// FairnessReport compares how many assignments each active member of a team
+// received in a window with an equal split.
+type FairnessReport struct {
+	TeamName         string  `json:"team_name"`
+	From             *string `json:"from,omitempty"`
+	To               *string `json:"to,omitempty"`
+	TotalAssignments int     `json:"total_assignments"`
+	ExpectedShare    float64 `json:"expected_share"`
+	Gini             float64 `json:"gini"`
+	// MaxMinRatio is nil when some member received no assignments.
+	MaxMinRatio *float64      `json:"max_min_ratio"`
+	Members     []MemberShare `json:"members"`
+}
+
+type MemberShare struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	Assignments int     `json:"assignments"`
+	Share       float64 `json:"share"`
+	// Outlier is FairnessOverloaded or FairnessUnderloaded when Share is
+	// further from the expected share than the tolerance allows.
+	Outlier string `json:"outlier,omitempty"`
+}
+
+// UnderReviewedPR is an open PR with fewer active reviewers than it
+// requires.
+type UnderReviewedPR struct {
 	PullRequestID     string   `json:"pull_request_id"`
 	PullRequestName   string   `json:"pull_request_name"`
 	AuthorID          string   `json:"author_id"`
-	Status            string   `json:"status"`
-	AssignedReviewers []string `json:"assigned_reviewers"`
-	CreatedAt         *string  `json:"createdAt,omitempty"`
-	MergedAt          *string  `json:"mergedAt,omitempty"`
+	TeamName          string   `json:"team_name"`
+	ReviewersRequired int      `json:"reviewers_required"`
+	ActiveReviewers   []string `json:"active_reviewers"`
+	Missing           int      `json:"missing"`
 }
 
 type PullRequestShort struct {
@@ -37,6 +306,109 @@ type PullRequestShort struct {
 	Status          string `json:"status"`
 }
 
+// ExternalAccount links a login on a code hosting provider to a user.
+type ExternalAccount struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
+}
+
+// PullRequestEvent is a provider-neutral pull request event received from
+// a code hosting webhook.
+type PullRequestEvent struct {
+	Provider        string
+	Action          string
+	PullRequestID   string
+	PullRequestName string
+	// AuthorLogin is empty when the event does not identify the author;
+	// such events never create a PR.
+	AuthorLogin string
+}
+
+// Event is a domain event published to outgoing webhook subscribers.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt string      `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// OutboxEntry is an event stored in the transactional outbox awaiting
+// publication by the relay.
+type OutboxEntry struct {
+	ID       int64
+	Event    Event
+	Attempts int
+	// TraceParent is the W3C traceparent of the request that produced the
+	// event, or "" if it was not traced.
+	TraceParent string
+}
+
+type WebhookSubscription struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+	IsActive   bool     `json:"is_active"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64   `json:"id"`
+	SubscriptionID int64   `json:"subscription_id"`
+	EventID        string  `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	NextAttemptAt  string  `json:"next_attempt_at"`
+	LastError      *string `json:"last_error,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	DeliveredAt    *string `json:"delivered_at,omitempty"`
+}
+
+// PendingWebhookDelivery is a delivery claimed by the worker together with
+// everything needed to send it.
+type PendingWebhookDelivery struct {
+	WebhookDelivery
+	URL         string
+	Secret      string
+	Payload     []byte
+	TraceParent string
+}
+
+type WebhookDeliveryAttempt struct {
+	ID          int64   `json:"id"`
+	DeliveryID  int64   `json:"delivery_id"`
+	Attempt     int     `json:"attempt"`
+	StatusCode  *int    `json:"status_code,omitempty"`
+	Error       *string `json:"error,omitempty"`
+	DurationMs  int64   `json:"duration_ms"`
+	AttemptedAt string  `json:"attempted_at"`
+}
+
+// WebhookAttemptResult is the outcome of a single delivery attempt as
+// reported by the delivery worker. Status is the delivery status after the
+// attempt; NextAttemptAt only matters while it stays DeliveryPending.
+type WebhookAttemptResult struct {
+	Attempt       int
+	StatusCode    *int
+	Error         *string
+	Duration      time.Duration
+	AttemptedAt   time.Time
+	Status        string
+	NextAttemptAt time.Time
+}
+
+type WebhookDeadLetter struct {
+	DeliveryID     int64   `json:"delivery_id"`
+	SubscriptionID int64   `json:"subscription_id"`
+	EventType      string  `json:"event_type"`
+	Payload        string  `json:"payload"`
+	Attempts       int     `json:"attempts"`
+	LastError      *string `json:"last_error,omitempty"`
+	FailedAt       string  `json:"failed_at"`
+}
+
 type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
 }
@@ -46,18 +418,153 @@ type ErrorDetail struct {
 	Message string `json:"message"`
 }
 
+// CodedError pairs an error code with a human readable message. Error returns
+// the bare code, so it compares equal to the plain errors.New(code) values
+// used throughout the service.
+type CodedError struct {
+	Code    string
+	Message string
+}
+
+func (e *CodedError) Error() string {
+	return e.Code
+}
+
+func NewError(code, message string) error {
+	return &CodedError{Code: code, Message: message}
+}
+
 const (
+	StatusDraft  = "DRAFT"
 	StatusOpen   = "OPEN"
 	StatusMerged = "MERGED"
+	StatusClosed = "CLOSED"
+
+	ErrTeamExists        = "TEAM_EXISTS"
+	ErrPRExists          = "PR_EXISTS"
+	ErrPoolExists        = "POOL_EXISTS"
+	ErrPRMerged          = "PR_MERGED"
+	ErrNotAssigned       = "NOT_ASSIGNED"
+	ErrNoCandidate       = "NO_CANDIDATE"
+	ErrUnderstaffed      = "UNDERSTAFFED"
+	ErrNotApproved       = "NOT_APPROVED"
+	ErrPRNotOpen         = "PR_NOT_OPEN"
+	ErrInvalidTransition = "INVALID_TRANSITION"
+	ErrNotFound          = "NOT_FOUND"
+	ErrInvalidInput      = "INVALID_INPUT"
+	ErrUnauthorized      = "UNAUTHORIZED"
+	ErrNotReady          = "NOT_READY"
 
-	ErrTeamExists  = "TEAM_EXISTS"
-	ErrPRExists    = "PR_EXISTS"
-	ErrPRMerged    = "PR_MERGED"
-	ErrNotAssigned = "NOT_ASSIGNED"
-	ErrNoCandidate = "NO_CANDIDATE"
-	ErrNotFound    = "NOT_FOUND"
+	VerdictApproved         = "APPROVED"
+	VerdictChangesRequested = "CHANGES_REQUESTED"
+	VerdictCommented        = "COMMENTED"
+
+	// StrategyRoundRobin resumes after the candidate most recently given a
+	// review. Concurrent PR creations may read the same cursor and pick the
+	// same reviewers; the rotation evens out on the next assignment.
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+
+	DefaultReviewersRequired = 2
+
+	DefaultStatisticsLimit = 10
+
+	CapacityReject      = "reject"
+	CapacityLeastLoaded = "least_loaded"
+
+	CodeOwnersPrefer  = "prefer"
+	CodeOwnersRequire = "require"
+
+	UnderstaffedAllow  = "allow"
+	UnderstaffedWarn   = "warn"
+	UnderstaffedReject = "reject"
+
+	SLAEscalate = "escalate"
+	SLAReassign = "reassign"
+
+	SLAActionEscalated  = "escalated"
+	SLAActionReassigned = "reassigned"
+
+	FairnessOverloaded  = "overloaded"
+	FairnessUnderloaded = "underloaded"
+
+	SourceTeam       = "team"
+	SourceCodeOwners = "code_owners"
+	SourcePool       = "pool"
+
+	DateLayout = "2006-01-02"
+
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+
+	EventOpened   = "opened"
+	EventMerged   = "merged"
+	EventClosed   = "closed"
+	EventReopened = "reopened"
+	EventUpdated  = "updated"
+
+	EventResultCreated  = "created"
+	EventResultMerged   = "merged"
+	EventResultClosed   = "closed"
+	EventResultReopened = "reopened"
+	EventResultIgnored  = "ignored"
+
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventReviewSubmitted    = "review.submitted"
+	EventPRMerged           = "pr.merged"
+	EventPRReady            = "pr.ready_for_review"
+	EventPRClosed           = "pr.closed"
+	EventPRReopened         = "pr.reopened"
+	EventTeamDeactivated    = "team.deactivated"
+	EventSLABreached        = "review.sla_breached"
+
+	AssignmentAssigned   = "assigned"
+	AssignmentUnassigned = "unassigned"
+	AssignmentReassigned = "reassigned"
+
+	ReasonAuto             = "auto"
+	ReasonManual           = "manual"
+	ReasonTeamDeactivation = "team_deactivation"
+	ReasonOOO              = "ooo"
+	ReasonSLA              = "sla"
+
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
 )
 
+// FormatTime formats t as an RFC 3339 timestamp in UTC.
 func FormatTime(t time.Time) string {
-	return t.Format(time.RFC3339)
+	return t.UTC().Format(time.RFC3339)
+}
+
+// EventTypes lists every domain event type the service publishes.
+var EventTypes = []string{
+	EventReviewerAssigned,
+	EventReviewerReassigned,
+	EventReviewSubmitted,
+	EventPRMerged,
+	EventPRReady,
+	EventPRClosed,
+	EventPRReopened,
+	EventTeamDeactivated,
+	EventSLABreached,
+}
+
+func NewEvent(eventType string, data interface{}) Event {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return Event{
+		ID:         hex.EncodeToString(id),
+		Type:       eventType,
+		OccurredAt: FormatTime(time.Now()),
+		Data:       data,
+	}
+}
+
+// FormatDate formats t as a calendar date in UTC.
+func FormatDate(t time.Time) string {
+	return t.UTC().Format(DateLayout)
 }
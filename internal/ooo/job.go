@@ -0,0 +1,54 @@
+// Package ooo periodically moves open reviews away from reviewers who are
+// out of office.
+package ooo
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"pr-reviewer-service/internal/health"
+	"pr-reviewer-service/internal/service"
+)
+
+type Job struct {
+	service   *service.Service
+	interval  time.Duration
+	heartbeat *health.Heartbeat
+}
+
+// NewJob returns a job that runs every interval. A multi-node deployment
+// should enable it on one node only; running it twice is harmless but
+// wasteful, since a reassigned review no longer belongs to the absent user.
+func NewJob(svc *service.Service, interval time.Duration) *Job {
+	return &Job{service: svc, interval: interval}
+}
+
+// SetHeartbeat makes the job report the outcome of every run to hb.
+func (j *Job) SetHeartbeat(hb *health.Heartbeat) {
+	j.heartbeat = hb
+}
+
+// Run reassigns reviews once immediately and then every interval until ctx
+// is cancelled.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		reassigned, failed, err := j.service.ReassignUnavailableReviewers(ctx)
+		j.heartbeat.Beat(err)
+		if err != nil {
+			slog.ErrorContext(ctx, "OOO reassign job failed", "err", err)
+		}
+		if len(reassigned)+len(failed) > 0 {
+			slog.InfoContext(ctx, "OOO reassign job finished", "reassigned", reassigned, "no_replacement", failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/tracing"
+	"pr-reviewer-service/internal/webhook"
+)
+
+// EventPublisher delivers a domain event to some downstream system. The relay
+// may call Publish more than once for the same event, so implementations
+// should tolerate duplicates; Event.ID is stable across retries.
+type EventPublisher interface {
+	Publish(ctx context.Context, event model.Event) error
+}
+
+// WebhookPublisher fans events out to the outgoing webhook subscriptions by
+// queueing deliveries for the delivery worker.
+type WebhookPublisher struct {
+	store storage.Repository
+}
+
+func NewWebhookPublisher(store storage.Repository) *WebhookPublisher {
+	return &WebhookPublisher{store: store}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event model.Event) error {
+	_, err := p.store.EnqueueWebhookEvent(ctx, event)
+	return err
+}
+
+// LogPublisher writes each event as a single JSON line to w, which is
+// typically stdout or an append-only file.
+type LogPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLogPublisher(w io.Writer) *LogPublisher {
+	return &LogPublisher{w: w}
+}
+
+func (p *LogPublisher) Publish(_ context.Context, event model.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
+
+// HTTPPublisher POSTs each event as JSON to a fixed URL. When a secret is
+// set, the body is signed in X-Reviewer-Signature-256 the same way as
+// outgoing webhooks.
+type HTTPPublisher struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewHTTPPublisher(url, secret string, timeout time.Duration) *HTTPPublisher {
+	return &HTTPPublisher{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event model.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Reviewer-Event", event.Type)
+	req.Header.Set("X-Reviewer-Event-ID", event.ID)
+	if p.secret != "" {
+		req.Header.Set("X-Reviewer-Signature-256", webhook.Sign([]byte(p.secret), body))
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("publish %s to %s: unexpected status %d", event.Type, p.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiPublisher publishes every event to all of its publishers and fails if
+// any of them fails.
+type MultiPublisher []EventPublisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event model.Event) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,143 @@
+// Package outbox relays domain events that the storage layer recorded in the
+// transactional outbox to pluggable publishers.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pr-reviewer-service/internal/health"
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/retry"
+	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Config struct {
+	// PollInterval is how often the outbox is checked for pending events.
+	PollInterval time.Duration
+	// BatchSize caps how many events are claimed per poll.
+	BatchSize int
+	// Lease is how long a claimed event is hidden from other relays.
+	Lease time.Duration
+	// BaseBackoff is the delay after the first failed publish; it doubles on
+	// every further failure up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Lease:        30 * time.Second,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   10 * time.Minute,
+	}
+}
+
+type Relay struct {
+	store     storage.Repository
+	publisher EventPublisher
+	config    Config
+	now       func() time.Time
+	heartbeat *health.Heartbeat
+}
+
+func NewRelay(store storage.Repository, publisher EventPublisher, config Config) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// SetHeartbeat makes the relay report the outcome of every poll to hb.
+func (r *Relay) SetHeartbeat(hb *health.Heartbeat) {
+	r.heartbeat = hb
+}
+
+// Run publishes pending events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		_, err := r.ProcessPending(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Outbox relay failed", "err", err)
+		}
+		r.heartbeat.Beat(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush publishes pending events batch by batch until none are due or a
+// batch publishes nothing, which happens once the remaining events are
+// failing and waiting out their backoff. It is meant for shutdown, after Run
+// has stopped, so that events committed by the last requests are not left
+// for the next start.
+func (r *Relay) Flush(ctx context.Context) error {
+	for {
+		n, err := r.ProcessPending(ctx)
+		if err != nil || n == 0 {
+			return err
+		}
+	}
+}
+
+// ProcessPending publishes one batch of due events and returns how many
+// were published successfully.
+func (r *Relay) ProcessPending(ctx context.Context) (int, error) {
+	entries, err := r.store.ClaimOutboxEvents(ctx, r.now(), r.config.Lease, r.config.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox events: %w", err)
+	}
+
+	published := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+
+		if err := r.publish(ctx, entry); err != nil {
+			next := r.now().Add(retry.Backoff(r.config.BaseBackoff, r.config.MaxBackoff, entry.Attempts+1))
+			slog.WarnContext(ctx, "Outbox publish failed",
+				"event_type", entry.Event.Type, "event_id", entry.Event.ID, "attempt", entry.Attempts+1, "err", err)
+			if err := r.store.MarkOutboxFailed(ctx, entry.ID, err.Error(), next); err != nil {
+				return published, fmt.Errorf("mark outbox event %d failed: %w", entry.ID, err)
+			}
+			continue
+		}
+
+		if err := r.store.MarkOutboxPublished(ctx, entry.ID, r.now()); err != nil {
+			return published, fmt.Errorf("mark outbox event %d published: %w", entry.ID, err)
+		}
+		published++
+	}
+	return published, nil
+}
+
+// publish hands entry to the publisher inside a span that continues the
+// trace of the request that produced the event.
+func (r *Relay) publish(ctx context.Context, entry model.OutboxEntry) error {
+	ctx, span := tracing.Start(tracing.WithTraceParent(ctx, entry.TraceParent), "outbox.publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("event.type", entry.Event.Type),
+			attribute.String("event.id", entry.Event.ID)))
+	err := r.publisher.Publish(ctx, entry.Event)
+	tracing.End(span, err)
+	return err
+}
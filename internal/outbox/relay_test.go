@@ -0,0 +1,205 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/service"
+	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/tracing"
+)
+
+type recordingPublisher struct {
+	events []model.Event
+	fail   bool
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event model.Event) error {
+	if p.fail {
+		return errors.New("broker unavailable")
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func newStoreWithPR(t *testing.T) storage.Repository {
+	t.Helper()
+
+	store := storage.NewMemory()
+	svc := service.New(store)
+	team := model.Team{TeamName: "backend", Members: []model.TeamMember{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+		{UserID: "u3", Username: "u3", IsActive: true},
+	}}
+	if _, err := svc.CreateTeam(context.Background(), team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	if _, err := svc.CreatePR(context.Background(), "pr-1", "Add feature", "u1", service.CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if _, err := svc.MergePR(context.Background(), "pr-1", service.MergeOptions{}); err != nil {
+		t.Fatalf("Failed to merge PR: %v", err)
+	}
+	return store
+}
+
+func TestRelayPublishesOutboxEvents(t *testing.T) {
+	store := newStoreWithPR(t)
+	publisher := &recordingPublisher{}
+	relay := NewRelay(store, publisher, DefaultConfig())
+
+	n, err := relay.ProcessPending(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessPending failed: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("Expected 3 published events, got %d", n)
+	}
+
+	types := []string{publisher.events[0].Type, publisher.events[1].Type, publisher.events[2].Type}
+	expected := []string{model.EventReviewerAssigned, model.EventReviewerAssigned, model.EventPRMerged}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected events %v, got %v", expected, types)
+			break
+		}
+	}
+
+	if n, _ := relay.ProcessPending(context.Background()); n != 0 {
+		t.Errorf("Expected published events not to be republished, got %d", n)
+	}
+}
+
+func TestRelayRetriesFailedPublish(t *testing.T) {
+	store := newStoreWithPR(t)
+	publisher := &recordingPublisher{fail: true}
+	relay := NewRelay(store, publisher, DefaultConfig())
+
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+
+	if n, _ := relay.ProcessPending(context.Background()); n != 0 {
+		t.Fatalf("Expected nothing published while failing, got %d", n)
+	}
+
+	publisher.fail = false
+	if n, _ := relay.ProcessPending(context.Background()); n != 0 {
+		t.Fatalf("Expected failed events to wait for backoff, got %d", n)
+	}
+
+	now = now.Add(DefaultConfig().BaseBackoff)
+	if n, _ := relay.ProcessPending(context.Background()); n != 3 {
+		t.Fatalf("Expected 3 events after backoff, got %d", n)
+	}
+}
+
+func TestRelayFlushPublishesAllBatches(t *testing.T) {
+	store := newStoreWithPR(t)
+	publisher := &recordingPublisher{}
+	config := DefaultConfig()
+	config.BatchSize = 1
+	relay := NewRelay(store, publisher, config)
+
+	if err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if len(publisher.events) != 3 {
+		t.Errorf("Expected all 3 events published in batches of 1, got %d", len(publisher.events))
+	}
+}
+
+func TestRelayFlushStopsOnFailingPublisher(t *testing.T) {
+	store := newStoreWithPR(t)
+	relay := NewRelay(store, &recordingPublisher{fail: true}, DefaultConfig())
+
+	if err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+}
+
+func TestLogPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewLogPublisher(&buf)
+	p.Publish(context.Background(), model.NewEvent(model.EventPRMerged, map[string]interface{}{"pull_request_id": "pr-1"}))
+	p.Publish(context.Background(), model.NewEvent(model.EventTeamDeactivated, nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	var event model.Event
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil || event.Type != model.EventPRMerged {
+		t.Errorf("Unexpected first line %q: %v", lines[0], err)
+	}
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	var gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get("X-Reviewer-Event")
+		if r.Header.Get("X-Reviewer-Signature-256") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := model.NewEvent(model.EventPRMerged, nil)
+	if err := NewHTTPPublisher(server.URL, "s3cret", time.Second).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if gotType != model.EventPRMerged {
+		t.Errorf("Expected event header %s, got %s", model.EventPRMerged, gotType)
+	}
+
+	if err := NewHTTPPublisher(server.URL, "", time.Second).Publish(context.Background(), event); err == nil {
+		t.Error("Expected non-2xx response to fail")
+	}
+}
+
+func TestRelayPropagatesTraceContext(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	var traceParents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	store := storage.NewMemory()
+	svc := service.New(store)
+	ctx := tracing.WithTraceParent(context.Background(), "00-"+traceID+"-00f067aa0ba902b7-01")
+	team := model.Team{TeamName: "backend", Members: []model.TeamMember{
+		{UserID: "u1", Username: "u1", IsActive: true},
+		{UserID: "u2", Username: "u2", IsActive: true},
+	}}
+	if _, err := svc.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	if _, err := svc.CreatePR(ctx, "pr-1", "Add feature", "u1", service.CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+
+	relay := NewRelay(store, NewHTTPPublisher(server.URL, "", time.Second), DefaultConfig())
+	if _, err := relay.ProcessPending(context.Background()); err != nil {
+		t.Fatalf("ProcessPending failed: %v", err)
+	}
+	if len(traceParents) == 0 {
+		t.Fatal("Expected the reviewer assignment to be published")
+	}
+	for _, tp := range traceParents {
+		if !strings.HasPrefix(tp, "00-"+traceID+"-") {
+			t.Errorf("Expected traceparent of trace %s, got %q", traceID, tp)
+		}
+	}
+}
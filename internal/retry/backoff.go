@@ -0,0 +1,19 @@
+// Package retry holds the retry policy shared by the background workers that
+// re-attempt failed work: the outbox relay and the webhook delivery worker.
+package retry
+
+import "time"
+
+// Backoff returns the delay before the attempt following failed attempt
+// number n: base after the first failure, doubling on every further failure
+// up to max.
+func Backoff(base, max time.Duration, n int) time.Duration {
+	delay := base
+	for i := 1; i < n; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,24 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{60, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := Backoff(time.Second, 5*time.Second, tt.attempt); got != tt.want {
+			t.Errorf("Attempt %d: expected %v, got %v", tt.attempt, tt.want, got)
+		}
+	}
+}
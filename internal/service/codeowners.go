@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"pr-reviewer-service/internal/codeowners"
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// SetCodeOwners replaces the ownership rules of teamName. content is parsed
+// up front so that a broken file is rejected instead of stored; empty
+// content removes all rules.
+func (s *Service) SetCodeOwners(ctx context.Context, teamName, content string) (*model.CodeOwners, codeowners.Ruleset, error) {
+	ctx, span := tracing.Start(ctx, "service.SetCodeOwners")
+	defer span.End()
+
+	rules, err := codeowners.Parse(content)
+	if err != nil {
+		return nil, nil, model.NewError(model.ErrInvalidInput, err.Error())
+	}
+
+	exists, err := s.store.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, errors.New(model.ErrNotFound)
+	}
+
+	owners, err := s.store.SetTeamCodeOwners(ctx, teamName, content)
+	if err != nil {
+		return nil, nil, err
+	}
+	return owners, rules, nil
+}
+
+// GetCodeOwners returns the stored rules of teamName. A team that never
+// uploaded any has empty content.
+func (s *Service) GetCodeOwners(ctx context.Context, teamName string) (*model.CodeOwners, codeowners.Ruleset, error) {
+	ctx, span := tracing.Start(ctx, "service.GetCodeOwners")
+	defer span.End()
+
+	exists, err := s.store.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, errors.New(model.ErrNotFound)
+	}
+
+	owners, err := s.store.GetTeamCodeOwners(ctx, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if owners == nil {
+		return &model.CodeOwners{TeamName: teamName}, codeowners.Ruleset{}, nil
+	}
+	rules, err := codeowners.Parse(owners.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	return owners, rules, nil
+}
+
+// normalizeChangedFiles cleans the changed file paths of a PR, drops
+// duplicates and sorts them the way storage returns them.
+func normalizeChangedFiles(files []string) ([]string, error) {
+	seen := make(map[string]bool, len(files))
+	normalized := []string{}
+	for _, f := range files {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			return nil, model.NewError(model.ErrInvalidInput, "changed_files must not contain empty paths")
+		}
+		f = strings.TrimPrefix(path.Clean("/"+f), "/")
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		normalized = append(normalized, f)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// codeOwnerCandidates resolves the owners of files under the CODEOWNERS
+// rules of teamName into active, available users other than the author.
+// matched reports whether any file has owners at all, so that callers can
+// tell "no rules apply" from "nobody who owns these files can review".
+//
+// "@org/name" refers to team name. "@name" is looked up as a user_id, then
+// as a linked GitHub login and finally as a team name. E-mail owners are
+// not resolvable and are ignored.
+func (s *Service) codeOwnerCandidates(ctx context.Context, teamName string, author *model.User, files []string) (candidates []model.User, matched bool, err error) {
+	if len(files) == 0 {
+		return nil, false, nil
+	}
+	stored, err := s.store.GetTeamCodeOwners(ctx, teamName)
+	if err != nil || stored == nil {
+		return nil, false, err
+	}
+	rules, err := codeowners.Parse(stored.Content)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tokens := []string{}
+	seenTokens := map[string]bool{}
+	for _, f := range files {
+		for _, owner := range rules.Owners(f) {
+			matched = true
+			if strings.HasPrefix(owner, "@") && !seenTokens[owner] {
+				seenTokens[owner] = true
+				tokens = append(tokens, owner)
+			}
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, matched, nil
+	}
+
+	for _, token := range tokens {
+		members, err := s.resolveCodeOwner(ctx, strings.TrimPrefix(token, "@"), author.UserID)
+		if err != nil {
+			return nil, false, err
+		}
+		candidates = appendNew(candidates, members)
+	}
+	return candidates, matched, nil
+}
+
+// resolveCodeOwner turns one owner name into the active, available users
+// it stands for, leaving out excludeUserID.
+func (s *Service) resolveCodeOwner(ctx context.Context, name, excludeUserID string) ([]model.User, error) {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return s.store.GetActiveTeamMembers(ctx, name[i+1:], excludeUserID, s.now())
+	}
+
+	user, err := s.store.GetUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		if user, err = s.store.GetUserByExternalLogin(ctx, model.ProviderGitHub, name); err != nil {
+			return nil, err
+		}
+	}
+	if user == nil {
+		return s.store.GetActiveTeamMembers(ctx, name, excludeUserID, s.now())
+	}
+
+	if !user.IsActive || user.UserID == excludeUserID {
+		return nil, nil
+	}
+	awayIDs, err := s.store.GetUnavailableUserIDs(ctx, s.now())
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range awayIDs {
+		if id == user.UserID {
+			return nil, nil
+		}
+	}
+	return []model.User{*user}, nil
+}
+
+// appendNew appends the users of more that are not yet in users.
+func appendNew(users, more []model.User) []model.User {
+	have := make(map[string]bool, len(users))
+	for _, u := range users {
+		have[u.UserID] = true
+	}
+	for _, u := range more {
+		if !have[u.UserID] {
+			have[u.UserID] = true
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+// excludeUsers returns users without the ones whose IDs are in userIDs.
+func excludeUsers(users []model.User, userIDs []string) []model.User {
+	skip := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		skip[id] = true
+	}
+	rest := []model.User{}
+	for _, u := range users {
+		if !skip[u.UserID] {
+			rest = append(rest, u)
+		}
+	}
+	return rest
+}
+
+// noCodeOwnerError is returned in CodeOwnersRequire mode when none of the
+// owners of the changed files can be assigned.
+func noCodeOwnerError(teamName string) error {
+	return model.NewError(model.ErrNoCandidate, fmt.Sprintf(
+		"team %s requires a code owner, but no owner of the changed files is available", teamName))
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestCodeOwnersReviewerSelection(t *testing.T) {
+	svc := newTestService(t,
+		testTeam("backend", "u1", "u2", "u3", "u4"),
+		testTeam("frontend", "f1", "f2"),
+	)
+
+	content := "* @u2\n/web/ @org/frontend\n/docs/ # nobody owns docs\n"
+	if _, _, err := svc.SetCodeOwners(t.Context(), "backend", content); err != nil {
+		t.Fatalf("Failed to set CODEOWNERS: %v", err)
+	}
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Restyle", "u1", CreatePROptions{
+		ChangedFiles: []string{"/web/app.js", "web/app.js", "web/../web/index.html"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if want := []string{"web/app.js", "web/index.html"}; !reflect.DeepEqual(pr.ChangedFiles, want) {
+		t.Errorf("Expected changed files %v, got %v", want, pr.ChangedFiles)
+	}
+	for _, reviewerID := range pr.AssignedReviewers {
+		if reviewerID != "f1" && reviewerID != "f2" {
+			t.Errorf("Expected frontend owners only, got %v", pr.AssignedReviewers)
+		}
+	}
+
+	pr, err = svc.CreatePR(t.Context(), "pr-2", "Fix bug", "u1", CreatePROptions{ChangedFiles: []string{"main.go"}})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 2 || !isAssigned(pr, "u2") {
+		t.Errorf("Expected owner u2 plus one teammate, got %v", pr.AssignedReviewers)
+	}
+
+	// Without owners available, prefer falls back to teammates and require
+	// refuses to assign.
+	svc.SetUserActive(t.Context(), "f1", false)
+	svc.SetUserActive(t.Context(), "f2", false)
+	if _, err := svc.CreatePR(t.Context(), "pr-3", "Restyle", "u1", CreatePROptions{ChangedFiles: []string{"web/app.js"}}); err != nil {
+		t.Fatalf("Expected prefer mode to fall back to teammates, got %v", err)
+	}
+	mode := model.CodeOwnersRequire
+	if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{CodeOwnersMode: &mode}); err != nil {
+		t.Fatalf("Failed to update team: %v", err)
+	}
+	if _, err := svc.CreatePR(t.Context(), "pr-4", "Restyle", "u1", CreatePROptions{ChangedFiles: []string{"web/app.js"}}); err == nil || err.Error() != model.ErrNoCandidate {
+		t.Errorf("Expected %s in require mode, got %v", model.ErrNoCandidate, err)
+	}
+
+	// Files without owners are not gated by require mode.
+	if _, err := svc.CreatePR(t.Context(), "pr-5", "Docs", "u1", CreatePROptions{ChangedFiles: []string{"docs/intro.md"}}); err != nil {
+		t.Errorf("Expected unowned files to use the team, got %v", err)
+	}
+}
+
+func TestSetCodeOwnersValidation(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2"))
+
+	if _, _, err := svc.SetCodeOwners(t.Context(), "backend", "!*.go @u1"); err == nil || err.Error() != model.ErrInvalidInput {
+		t.Errorf("Expected %s for a negation pattern, got %v", model.ErrInvalidInput, err)
+	}
+	if _, _, err := svc.SetCodeOwners(t.Context(), "nobody", "* @u1"); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s for an unknown team, got %v", model.ErrNotFound, err)
+	}
+
+	if _, _, err := svc.SetCodeOwners(t.Context(), "backend", "*.go @u2\n"); err != nil {
+		t.Fatalf("Failed to set CODEOWNERS: %v", err)
+	}
+	owners, rules, err := svc.GetCodeOwners(t.Context(), "backend")
+	if err != nil {
+		t.Fatalf("Failed to get CODEOWNERS: %v", err)
+	}
+	if owners.Content != "*.go @u2\n" || len(rules) != 1 || rules[0].Pattern != "*.go" {
+		t.Errorf("Unexpected CODEOWNERS %+v %+v", owners, rules)
+	}
+}
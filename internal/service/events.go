@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+func (s *Service) LinkExternalAccount(ctx context.Context, account model.ExternalAccount) (*model.ExternalAccount, error) {
+	ctx, span := tracing.Start(ctx, "service.LinkExternalAccount")
+	defer span.End()
+
+	if account.Provider != model.ProviderGitHub && account.Provider != model.ProviderGitLab {
+		return nil, model.NewError(model.ErrInvalidInput, fmt.Sprintf("unsupported provider %q", account.Provider))
+	}
+	if account.Login == "" {
+		return nil, model.NewError(model.ErrInvalidInput, "login is required")
+	}
+	account.Login = strings.ToLower(account.Login)
+
+	user, err := s.store.GetUser(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(model.ErrNotFound)
+	}
+
+	if err := s.store.LinkExternalAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ApplyPullRequestEvent translates a webhook event into service operations.
+// It reports what was done as one of the model.EventResult* values together
+// with the resulting PR, if any. Redelivered events are idempotent: opening
+// a PR that already exists leaves it untouched and reports it as ignored,
+// and so does closing one that is already closed or merged. An event for an
+// unknown PR that does not name its author is ignored as well.
+func (s *Service) ApplyPullRequestEvent(ctx context.Context, ev model.PullRequestEvent) (string, *model.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "service.ApplyPullRequestEvent")
+	defer span.End()
+
+	switch ev.Action {
+	case model.EventOpened, model.EventReopened, model.EventUpdated:
+		existing, err := s.store.GetPR(ctx, ev.PullRequestID)
+		if err != nil {
+			return "", nil, err
+		}
+		if existing != nil {
+			if ev.Action == model.EventReopened && existing.Status == model.StatusClosed {
+				pr, err := s.ReopenPR(ctx, ev.PullRequestID)
+				if err != nil {
+					return "", nil, err
+				}
+				return model.EventResultReopened, pr, nil
+			}
+			return model.EventResultIgnored, existing, nil
+		}
+		if ev.AuthorLogin == "" {
+			return model.EventResultIgnored, nil, nil
+		}
+
+		author, err := s.store.GetUserByExternalLogin(ctx, ev.Provider, strings.ToLower(ev.AuthorLogin))
+		if err != nil {
+			return "", nil, err
+		}
+		if author == nil {
+			return "", nil, model.NewError(model.ErrNotFound,
+				fmt.Sprintf("no user linked to %s login %q", ev.Provider, ev.AuthorLogin))
+		}
+
+		pr, err := s.CreatePR(ctx, ev.PullRequestID, ev.PullRequestName, author.UserID, CreatePROptions{})
+		if err != nil {
+			if err.Error() == model.ErrPRExists {
+				// A concurrent delivery of the same event created it first.
+				pr, err = s.store.GetPR(ctx, ev.PullRequestID)
+				return model.EventResultIgnored, pr, err
+			}
+			return "", nil, err
+		}
+		return model.EventResultCreated, pr, nil
+
+	case model.EventMerged:
+		pr, err := s.store.GetPR(ctx, ev.PullRequestID)
+		if err != nil {
+			return "", nil, err
+		}
+		if pr == nil {
+			return model.EventResultIgnored, nil, nil
+		}
+		if pr.Status == model.StatusMerged {
+			return model.EventResultMerged, pr, nil
+		}
+		// The merge already happened upstream, so the approval policy is not
+		// consulted: refusing it would only leave the PR stuck as OPEN here.
+		pr, err = s.merge(ctx, pr, nil)
+		if err != nil {
+			return "", nil, err
+		}
+		return model.EventResultMerged, pr, nil
+
+	case model.EventClosed:
+		pr, err := s.store.GetPR(ctx, ev.PullRequestID)
+		if err != nil {
+			return "", nil, err
+		}
+		if pr == nil || pr.Status == model.StatusMerged || pr.Status == model.StatusClosed {
+			return model.EventResultIgnored, pr, nil
+		}
+		pr, err = s.ClosePR(ctx, ev.PullRequestID)
+		if err != nil {
+			return "", nil, err
+		}
+		return model.EventResultClosed, pr, nil
+	}
+
+	return model.EventResultIgnored, nil, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// fairnessTolerance is how far, relative to the expected share, a member's
+// share may drift before they are flagged as an outlier.
+const fairnessTolerance = 0.5
+
+// GetFairnessReport compares the assignments each active member of
+// teamName received in the window between from and to (see
+// StatisticsOptions) with an equal split across the team's active roster.
+func (s *Service) GetFairnessReport(ctx context.Context, teamName, from, to string) (*model.FairnessReport, error) {
+	ctx, span := tracing.Start(ctx, "service.GetFairnessReport")
+	defer span.End()
+
+	if teamName == "" {
+		return nil, model.NewError(model.ErrInvalidInput, "team_name is required")
+	}
+	start, end, err := parseWindow(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.store.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, errors.New(model.ErrNotFound)
+	}
+
+	userIDs := []string{}
+	for _, m := range team.Members {
+		if m.IsActive {
+			userIDs = append(userIDs, m.UserID)
+		}
+	}
+	counts, err := s.store.GetAssignmentCounts(ctx, userIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.FairnessReport{TeamName: teamName, Members: []model.MemberShare{}}
+	report.From, report.To = formatWindow(start, end)
+	for _, m := range team.Members {
+		if m.IsActive {
+			report.Members = append(report.Members, model.MemberShare{
+				UserID:      m.UserID,
+				Username:    m.Username,
+				Assignments: counts[m.UserID],
+			})
+			report.TotalAssignments += counts[m.UserID]
+		}
+	}
+	if len(report.Members) == 0 {
+		return report, nil
+	}
+
+	report.ExpectedShare = 1 / float64(len(report.Members))
+	values := make([]int, len(report.Members))
+	for i := range report.Members {
+		m := &report.Members[i]
+		values[i] = m.Assignments
+		if report.TotalAssignments == 0 {
+			continue
+		}
+		m.Share = float64(m.Assignments) / float64(report.TotalAssignments)
+		switch {
+		case m.Share > report.ExpectedShare*(1+fairnessTolerance):
+			m.Outlier = model.FairnessOverloaded
+		case m.Share < report.ExpectedShare*(1-fairnessTolerance):
+			m.Outlier = model.FairnessUnderloaded
+		}
+	}
+	report.Gini = gini(values)
+
+	sort.Ints(values)
+	if lowest, highest := values[0], values[len(values)-1]; lowest > 0 {
+		ratio := float64(highest) / float64(lowest)
+		report.MaxMinRatio = &ratio
+	}
+	return report, nil
+}
+
+// gini returns the Gini coefficient of values: 0 when they are all equal,
+// approaching 1 as they concentrate on one member.
+func gini(values []int) float64 {
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+
+	total, weighted := 0, 0
+	for i, v := range sorted {
+		total += v
+		weighted += (i + 1) * v
+	}
+	if total == 0 {
+		return 0
+	}
+	n := float64(len(sorted))
+	return 2*float64(weighted)/(n*float64(total)) - (n+1)/n
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestGini(t *testing.T) {
+	cases := []struct {
+		values []int
+		want   float64
+	}{
+		{[]int{3, 3, 3}, 0},
+		{[]int{0, 0, 0}, 0},
+		{[]int{0, 0, 0, 4}, 0.75},
+		{[]int{1, 3}, 0.25},
+	}
+	for _, tc := range cases {
+		if got := gini(tc.values); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("gini(%v) = %v, want %v", tc.values, got, tc.want)
+		}
+	}
+}
+
+func TestFairnessReport(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+	if _, err := svc.SetUserActive(t.Context(), "u4", false); err != nil {
+		t.Fatalf("Failed to deactivate user: %v", err)
+	}
+
+	// u1 authors everything, so u2 and u3 take all the reviews and u1, the
+	// remaining active member, gets none.
+	for _, id := range []string{"pr-1", "pr-2"} {
+		if _, err := svc.CreatePR(t.Context(), id, "Change", "u1", CreatePROptions{}); err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+	}
+
+	report, err := svc.GetFairnessReport(t.Context(), "backend", "", "")
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if len(report.Members) != 3 || report.TotalAssignments != 4 {
+		t.Fatalf("Expected 3 active members with 4 assignments, got %+v", report)
+	}
+	if math.Abs(report.ExpectedShare-1.0/3) > 1e-9 || report.MaxMinRatio != nil {
+		t.Errorf("Expected a third each and no max/min ratio, got %+v", report)
+	}
+	if math.Abs(report.Gini-gini([]int{0, 2, 2})) > 1e-9 {
+		t.Errorf("Unexpected gini %v", report.Gini)
+	}
+	outliers := map[string]string{}
+	for _, m := range report.Members {
+		outliers[m.UserID] = m.Outlier
+	}
+	if outliers["u1"] != model.FairnessUnderloaded || outliers["u2"] != "" || outliers["u3"] != "" {
+		t.Errorf("Expected only u1 flagged as underloaded, got %v", outliers)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(model.DateLayout)
+	if empty, _ := svc.GetFairnessReport(t.Context(), "backend", tomorrow, ""); empty.TotalAssignments != 0 || empty.Gini != 0 {
+		t.Errorf("Expected no assignments in the future, got %+v", empty)
+	}
+
+	if _, err := svc.GetFairnessReport(t.Context(), "", "", ""); err == nil || err.Error() != model.ErrInvalidInput {
+		t.Errorf("Expected %s without a team, got %v", model.ErrInvalidInput, err)
+	}
+	if _, err := svc.GetFairnessReport(t.Context(), "nope", "", ""); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s for an unknown team, got %v", model.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"pr-reviewer-service/internal/metrics"
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// prTransitions lists the statuses a PR may move to from each status.
+// MERGED is terminal.
+var prTransitions = map[string][]string{
+	model.StatusDraft:  {model.StatusOpen, model.StatusClosed},
+	model.StatusOpen:   {model.StatusMerged, model.StatusClosed},
+	model.StatusClosed: {model.StatusOpen},
+}
+
+func checkTransition(from, to string) error {
+	if from == model.StatusMerged {
+		return errors.New(model.ErrPRMerged)
+	}
+	for _, allowed := range prTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return model.NewError(model.ErrInvalidTransition,
+		fmt.Sprintf("cannot move PR from %s to %s", from, to))
+}
+
+// requireOpen rejects reviewer operations on PRs that are not OPEN.
+func requireOpen(pr *model.PullRequest) error {
+	switch pr.Status {
+	case model.StatusOpen:
+		return nil
+	case model.StatusMerged:
+		return errors.New(model.ErrPRMerged)
+	}
+	return model.NewError(model.ErrPRNotOpen, fmt.Sprintf("PR is %s", pr.Status))
+}
+
+// MarkReady moves a DRAFT PR to OPEN and assigns its reviewers.
+// reviewersRequired is the optional per-PR override, as in CreatePROptions.
+func (s *Service) MarkReady(ctx context.Context, prID string, reviewersRequired int) (*model.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "service.MarkReady")
+	defer span.End()
+
+	pr, err := s.getPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == model.StatusOpen {
+		return pr, nil
+	}
+	if err := checkTransition(pr.Status, model.StatusOpen); err != nil {
+		return nil, err
+	}
+	if pr.Status != model.StatusDraft {
+		return nil, model.NewError(model.ErrInvalidTransition, "only a DRAFT PR can be marked ready")
+	}
+
+	reviewers, required, err := s.reviewersFor(ctx, pr, reviewersRequired)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, pr, model.StatusOpen, required, reviewers, model.EventPRReady)
+}
+
+// ClosePR closes a DRAFT or OPEN PR without merging it. Its reviewers stay
+// assigned but no longer count as busy with it.
+func (s *Service) ClosePR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "service.ClosePR")
+	defer span.End()
+
+	pr, err := s.getPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == model.StatusClosed {
+		return pr, nil
+	}
+	if err := checkTransition(pr.Status, model.StatusClosed); err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, pr, model.StatusClosed, 0, nil, model.EventPRClosed)
+}
+
+// ReopenPR moves a CLOSED PR back to OPEN. A PR that was closed as a draft
+// gets its reviewers assigned now.
+func (s *Service) ReopenPR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "service.ReopenPR")
+	defer span.End()
+
+	pr, err := s.getPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == model.StatusOpen {
+		return pr, nil
+	}
+	if err := checkTransition(pr.Status, model.StatusOpen); err != nil {
+		return nil, err
+	}
+	if pr.Status != model.StatusClosed {
+		return nil, model.NewError(model.ErrInvalidTransition, "only a CLOSED PR can be reopened")
+	}
+
+	var reviewers []model.ReviewerSource
+	required := 0
+	if len(pr.AssignedReviewers) == 0 {
+		if reviewers, required, err = s.reviewersFor(ctx, pr, 0); err != nil {
+			return nil, err
+		}
+	}
+	return s.transition(ctx, pr, model.StatusOpen, required, reviewers, model.EventPRReopened)
+}
+
+func (s *Service) getPR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	pr, err := s.store.GetPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, errors.New(model.ErrNotFound)
+	}
+	return pr, nil
+}
+
+func (s *Service) reviewersFor(ctx context.Context, pr *model.PullRequest, reviewersRequired int) ([]model.ReviewerSource, int, error) {
+	author, err := s.store.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if author == nil {
+		return nil, 0, errors.New(model.ErrNotFound)
+	}
+	return s.pickReviewers(ctx, author, reviewersRequired, pr.ChangedFiles)
+}
+
+// transition stores the status change of pr together with an eventType
+// event and one reviewer.assigned event per newly assigned reviewer.
+// reviewersRequired, when non-zero, is stored as the PR's reviewer count.
+func (s *Service) transition(ctx context.Context, pr *model.PullRequest, to string, reviewersRequired int, reviewers []model.ReviewerSource, eventType string) (*model.PullRequest, error) {
+	events := []model.Event{model.NewEvent(eventType, map[string]interface{}{
+		"pull_request_id": pr.PullRequestID,
+		"author_id":       pr.AuthorID,
+		"from_status":     pr.Status,
+		"to_status":       to,
+	})}
+	events = append(events, assignedEvents(pr, reviewers)...)
+
+	err := s.store.TransitionPR(ctx, pr.PullRequestID, pr.Status, to, reviewersRequired, reviewers, events...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewError(model.ErrInvalidTransition, "PR status changed concurrently")
+	}
+	if err != nil {
+		return nil, err
+	}
+	metrics.Assigned(model.AssignmentAssigned, model.ReasonAuto, len(reviewers))
+	slog.InfoContext(ctx, "Pull request status changed",
+		"pull_request_id", pr.PullRequestID, "from_status", pr.Status, "to_status", to, "reviewers", reviewerIDs(reviewers))
+	return s.store.GetPR(ctx, pr.PullRequestID)
+}
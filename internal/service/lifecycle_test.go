@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/storage"
+)
+
+func TestPRLifecycle(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+
+	draft, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{Draft: true})
+	if err != nil {
+		t.Fatalf("Failed to create draft: %v", err)
+	}
+	if draft.Status != model.StatusDraft || len(draft.AssignedReviewers) != 0 {
+		t.Fatalf("Expected DRAFT without reviewers, got %s %v", draft.Status, draft.AssignedReviewers)
+	}
+
+	if _, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{}); err == nil || err.Error() != model.ErrInvalidTransition {
+		t.Errorf("Expected %s merging a draft, got %v", model.ErrInvalidTransition, err)
+	}
+
+	ready, err := svc.MarkReady(t.Context(), "pr-1", 0)
+	if err != nil {
+		t.Fatalf("Failed to mark ready: %v", err)
+	}
+	if ready.Status != model.StatusOpen || len(ready.AssignedReviewers) != 2 {
+		t.Fatalf("Expected OPEN with 2 reviewers, got %s %v", ready.Status, ready.AssignedReviewers)
+	}
+	reviewer := ready.AssignedReviewers[0]
+
+	closed, err := svc.ClosePR(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	if closed.Status != model.StatusClosed || closed.ClosedAt == nil {
+		t.Errorf("Expected CLOSED with closedAt, got %s %v", closed.Status, closed.ClosedAt)
+	}
+
+	load, _ := svc.store.GetOpenReviewCounts(t.Context(), []string{reviewer})
+	if load[reviewer] != 0 {
+		t.Errorf("Expected closed PR not to count as open review, got %d", load[reviewer])
+	}
+	reviews, _ := svc.GetUserReviews(t.Context(), reviewer)
+	if len(reviews) != 0 {
+		t.Errorf("Expected closed PR hidden from getReview, got %+v", reviews)
+	}
+	if _, _, err := svc.SubmitReview(t.Context(), "pr-1", reviewer, model.VerdictApproved, nil); err == nil || err.Error() != model.ErrPRNotOpen {
+		t.Errorf("Expected %s reviewing a closed PR, got %v", model.ErrPRNotOpen, err)
+	}
+	if _, err := svc.MarkReady(t.Context(), "pr-1", 0); err == nil || err.Error() != model.ErrInvalidTransition {
+		t.Errorf("Expected %s marking a closed PR ready, got %v", model.ErrInvalidTransition, err)
+	}
+
+	reopened, err := svc.ReopenPR(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatalf("Failed to reopen: %v", err)
+	}
+	if reopened.Status != model.StatusOpen || reopened.ClosedAt != nil || len(reopened.AssignedReviewers) != 2 {
+		t.Errorf("Expected OPEN with the same reviewers, got %+v", reopened)
+	}
+
+	if _, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{}); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	if _, err := svc.ClosePR(t.Context(), "pr-1"); err == nil || err.Error() != model.ErrPRMerged {
+		t.Errorf("Expected %s closing a merged PR, got %v", model.ErrPRMerged, err)
+	}
+
+	stats, _ := svc.GetStatistics(t.Context(), StatisticsOptions{})
+	if stats.MergedPRs != 1 || stats.DraftPRs != 0 || stats.ClosedPRs != 0 {
+		t.Errorf("Unexpected statistics %v", stats)
+	}
+}
+
+func TestReopenClosedDraftAssignsReviewers(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3"))
+
+	if _, err := svc.CreatePR(t.Context(), "pr-1", "Spike", "u1", CreatePROptions{Draft: true}); err != nil {
+		t.Fatalf("Failed to create draft: %v", err)
+	}
+	if _, err := svc.ClosePR(t.Context(), "pr-1"); err != nil {
+		t.Fatalf("Failed to close draft: %v", err)
+	}
+
+	pr, err := svc.ReopenPR(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatalf("Failed to reopen: %v", err)
+	}
+	if pr.Status != model.StatusOpen || len(pr.AssignedReviewers) != 2 {
+		t.Errorf("Expected OPEN with 2 reviewers, got %s %v", pr.Status, pr.AssignedReviewers)
+	}
+}
+
+// staleStore returns a fixed copy of one PR, as a reader that loaded it
+// just before a concurrent change would see it.
+type staleStore struct {
+	storage.Repository
+	pr *model.PullRequest
+}
+
+func (s *staleStore) GetPR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	if prID == s.pr.PullRequestID {
+		return s.pr, nil
+	}
+	return s.Repository.GetPR(ctx, prID)
+}
+
+func TestMergeOfPRClosedConcurrently(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3"))
+
+	loaded, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if _, err := svc.ClosePR(t.Context(), "pr-1"); err != nil {
+		t.Fatalf("Failed to close PR: %v", err)
+	}
+
+	store := svc.store
+	svc.store = &staleStore{Repository: store, pr: loaded}
+	_, err = svc.MergePR(t.Context(), "pr-1", MergeOptions{})
+	svc.store = store
+	if err == nil || err.Error() != model.ErrInvalidTransition {
+		t.Fatalf("Expected %s, got %v", model.ErrInvalidTransition, err)
+	}
+
+	pr, _ := svc.store.GetPR(t.Context(), "pr-1")
+	if pr.Status != model.StatusClosed || pr.MergedAt != nil {
+		t.Errorf("Expected the PR to stay CLOSED, got %s (merged at %v)", pr.Status, pr.MergedAt)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// CreateReviewerPool declares a named pool of reviewers. Members may belong
+// to any team.
+func (s *Service) CreateReviewerPool(ctx context.Context, poolName string, members []string) (*model.ReviewerPool, error) {
+	ctx, span := tracing.Start(ctx, "service.CreateReviewerPool")
+	defer span.End()
+
+	if err := validatePoolName(poolName); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.GetReviewerPool(ctx, poolName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New(model.ErrPoolExists)
+	}
+	if err := s.checkUsersExist(ctx, members); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.store.CreateReviewerPool(ctx, model.ReviewerPool{PoolName: poolName, Members: members}); err != nil {
+		return nil, err
+	}
+	return s.store.GetReviewerPool(ctx, poolName)
+}
+
+// SetReviewerPoolMembers replaces the members of poolName.
+func (s *Service) SetReviewerPoolMembers(ctx context.Context, poolName string, members []string) (*model.ReviewerPool, error) {
+	ctx, span := tracing.Start(ctx, "service.SetReviewerPoolMembers")
+	defer span.End()
+
+	if err := s.checkUsersExist(ctx, members); err != nil {
+		return nil, err
+	}
+
+	err := s.store.SetReviewerPoolMembers(ctx, poolName, members)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.store.GetReviewerPool(ctx, poolName)
+}
+
+func (s *Service) GetReviewerPool(ctx context.Context, poolName string) (*model.ReviewerPool, error) {
+	ctx, span := tracing.Start(ctx, "service.GetReviewerPool")
+	defer span.End()
+
+	pool, err := s.store.GetReviewerPool(ctx, poolName)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, errors.New(model.ErrNotFound)
+	}
+	return pool, nil
+}
+
+func (s *Service) ListReviewerPools(ctx context.Context) ([]model.ReviewerPool, error) {
+	ctx, span := tracing.Start(ctx, "service.ListReviewerPools")
+	defer span.End()
+
+	return s.store.ListReviewerPools(ctx)
+}
+
+// DeleteReviewerPool removes the pool; teams that used it as a fallback
+// stop doing so.
+func (s *Service) DeleteReviewerPool(ctx context.Context, poolName string) error {
+	ctx, span := tracing.Start(ctx, "service.DeleteReviewerPool")
+	defer span.End()
+
+	err := s.store.DeleteReviewerPool(ctx, poolName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New(model.ErrNotFound)
+	}
+	return err
+}
+
+func validatePoolName(poolName string) error {
+	if strings.TrimSpace(poolName) == "" {
+		return model.NewError(model.ErrInvalidInput, "pool_name is required")
+	}
+	// Team settings keep fallback pools as a comma separated list.
+	if strings.Contains(poolName, ",") {
+		return model.NewError(model.ErrInvalidInput, "pool_name must not contain commas")
+	}
+	return nil
+}
+
+func (s *Service) checkUsersExist(ctx context.Context, userIDs []string) error {
+	for _, userID := range userIDs {
+		user, err := s.store.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return model.NewError(model.ErrInvalidInput, fmt.Sprintf("unknown user %s", userID))
+		}
+	}
+	return nil
+}
+
+// checkFallbackPools validates the fallback_pools setting of a team: every
+// pool must exist and appear once.
+func (s *Service) checkFallbackPools(ctx context.Context, poolNames []string) error {
+	seen := make(map[string]bool, len(poolNames))
+	for _, poolName := range poolNames {
+		if seen[poolName] {
+			return model.NewError(model.ErrInvalidInput, fmt.Sprintf("fallback pool %s is listed twice", poolName))
+		}
+		seen[poolName] = true
+
+		pool, err := s.store.GetReviewerPool(ctx, poolName)
+		if err != nil {
+			return err
+		}
+		if pool == nil {
+			return model.NewError(model.ErrInvalidInput, fmt.Sprintf("unknown reviewer pool %s", poolName))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestFallbackPools(t *testing.T) {
+	svc := newTestService(t,
+		testTeam("solo", "s1"),
+		testTeam("platform", "p1", "p2"),
+		testTeam("backend", "b1", "b2"),
+	)
+
+	if _, err := svc.CreateReviewerPool(t.Context(), "seniors", []string{"p1", "b1"}); err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	if _, err := svc.CreateReviewerPool(t.Context(), "platform", []string{"p2"}); err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	if _, err := svc.CreateReviewerPool(t.Context(), "seniors", nil); err == nil || err.Error() != model.ErrPoolExists {
+		t.Errorf("Expected %s, got %v", model.ErrPoolExists, err)
+	}
+
+	unknown := []string{"nobody"}
+	if _, err := svc.UpdateTeam(t.Context(), "solo", model.TeamSettingsUpdate{FallbackPools: &unknown}); err == nil || err.Error() != model.ErrInvalidInput {
+		t.Errorf("Expected %s for an unknown pool, got %v", model.ErrInvalidInput, err)
+	}
+
+	// Without fallback pools a one-person team gets nobody.
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Alone", "s1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 0 {
+		t.Fatalf("Expected no reviewers, got %v", pr.AssignedReviewers)
+	}
+
+	pools := []string{"platform", "seniors"}
+	if _, err := svc.UpdateTeam(t.Context(), "solo", model.TeamSettingsUpdate{FallbackPools: &pools}); err != nil {
+		t.Fatalf("Failed to set fallback pools: %v", err)
+	}
+	pr, err = svc.CreatePR(t.Context(), "pr-2", "Alone", "s1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if len(pr.ReviewerSources) != 2 {
+		t.Fatalf("Expected 2 reviewers from pools, got %+v", pr.ReviewerSources)
+	}
+	// Pools are tried in order: platform first, then seniors.
+	if got := pr.ReviewerSources[0]; got != (model.ReviewerSource{UserID: "p2", Source: model.SourcePool, Name: "platform"}) {
+		t.Errorf("Unexpected first reviewer %+v", got)
+	}
+	if got := pr.ReviewerSources[1]; got.Source != model.SourcePool || got.Name != "seniors" {
+		t.Errorf("Unexpected second reviewer %+v", got)
+	}
+
+	// A teammate comes first and the pool fills the remaining slot.
+	fallback := []string{"seniors"}
+	if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{FallbackPools: &fallback}); err != nil {
+		t.Fatalf("Failed to set fallback pools: %v", err)
+	}
+	pr, err = svc.CreatePR(t.Context(), "pr-3", "Fix", "b1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	want := []model.ReviewerSource{
+		{UserID: "b2", Source: model.SourceTeam, Name: "backend"},
+		{UserID: "p1", Source: model.SourcePool, Name: "seniors"},
+	}
+	if len(pr.ReviewerSources) != 2 || pr.ReviewerSources[0] != want[0] || pr.ReviewerSources[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, pr.ReviewerSources)
+	}
+
+	if err := svc.DeleteReviewerPool(t.Context(), "seniors"); err != nil {
+		t.Fatalf("Failed to delete pool: %v", err)
+	}
+	team, _ := svc.GetTeam(t.Context(), "backend")
+	if len(team.FallbackPools) != 0 {
+		t.Errorf("Expected deleted pool to be dropped from fallback_pools, got %v", team.FallbackPools)
+	}
+}
+
+func TestReplacementsUseFallbackPools(t *testing.T) {
+	svc := newTestService(t,
+		testTeam("solo", "s1"),
+		testTeam("platform", "p1", "p2"),
+		testTeam("backend", "b1", "b2"),
+	)
+	if _, err := svc.CreateReviewerPool(t.Context(), "platform", []string{"p1", "p2"}); err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	if _, err := svc.CreateReviewerPool(t.Context(), "backup", []string{"b1", "b2"}); err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	pools := []string{"platform", "backup"}
+	if _, err := svc.UpdateTeam(t.Context(), "solo", model.TeamSettingsUpdate{FallbackPools: &pools}); err != nil {
+		t.Fatalf("Failed to set fallback pools: %v", err)
+	}
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Alone", "s1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if !isAssigned(pr, "p1") || !isAssigned(pr, "p2") {
+		t.Fatalf("Expected both platform members, got %v", pr.AssignedReviewers)
+	}
+
+	// The platform pool is exhausted, so the next pool of the author's team
+	// fills in rather than p1's own team.
+	pr, replacedBy, err := svc.ReassignReviewer(t.Context(), "pr-1", "p1", "")
+	if err != nil {
+		t.Fatalf("Failed to reassign: %v", err)
+	}
+	if replacedBy != "b1" && replacedBy != "b2" {
+		t.Fatalf("Expected a backup pool member, got %s", replacedBy)
+	}
+	for _, r := range pr.ReviewerSources {
+		if r.UserID == replacedBy && (r.Source != model.SourcePool || r.Name != "backup") {
+			t.Errorf("Expected %s to come from pool backup, got %+v", replacedBy, r)
+		}
+	}
+
+	// Deactivating p2's team leaves it no teammates, but the author's pools
+	// still have a replacement.
+	result, err := svc.DeactivateTeam(t.Context(), "platform", "")
+	if err != nil {
+		t.Fatalf("Failed to deactivate team: %v", err)
+	}
+	if reassigned := result["reassigned_prs"].([]string); len(reassigned) != 1 || reassigned[0] != "pr-1" {
+		t.Errorf("Expected pr-1 reassigned, got %v (failed %v)", reassigned, result["failed_reassignments"])
+	}
+	pr, _ = svc.store.GetPR(t.Context(), "pr-1")
+	want := map[string]bool{"b1": true, "b2": true}
+	for _, r := range pr.ReviewerSources {
+		if !want[r.UserID] || r.Source != model.SourcePool || r.Name != "backup" {
+			t.Errorf("Expected backup pool reviewers only, got %+v", pr.ReviewerSources)
+		}
+	}
+}
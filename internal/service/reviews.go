@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// SubmitReview records a verdict from one of the PR's assigned reviewers.
+// A reviewer may submit several times; only the latest verdict counts.
+func (s *Service) SubmitReview(ctx context.Context, prID, userID, verdict string, comment *string) (*model.PullRequest, *model.Review, error) {
+	ctx, span := tracing.Start(ctx, "service.SubmitReview")
+	defer span.End()
+
+	if !isValidVerdict(verdict) {
+		return nil, nil, model.NewError(model.ErrInvalidInput, "verdict must be one of APPROVED, CHANGES_REQUESTED, COMMENTED")
+	}
+
+	pr, err := s.store.GetPR(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pr == nil {
+		return nil, nil, errors.New(model.ErrNotFound)
+	}
+	if err := requireOpen(pr); err != nil {
+		return nil, nil, err
+	}
+	if !isAssigned(pr, userID) {
+		return nil, nil, errors.New(model.ErrNotAssigned)
+	}
+
+	event := model.NewEvent(model.EventReviewSubmitted, map[string]interface{}{
+		"pull_request_id": prID,
+		"reviewer_id":     userID,
+		"verdict":         verdict,
+	})
+	review, err := s.store.AddReview(ctx, model.Review{
+		PullRequestID: prID,
+		UserID:        userID,
+		Verdict:       verdict,
+		Comment:       comment,
+	}, event)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The PR was merged or the reviewer reassigned since we read it.
+		return nil, nil, errors.New(model.ErrNotAssigned)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pr, err = s.store.GetPR(ctx, prID)
+	return pr, review, err
+}
+
+func isAssigned(pr *model.PullRequest, userID string) bool {
+	for _, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidVerdict(verdict string) bool {
+	switch verdict {
+	case model.VerdictApproved, model.VerdictChangesRequested, model.VerdictCommented:
+		return true
+	}
+	return false
+}
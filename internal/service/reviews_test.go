@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestSubmitReview(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	reviewer := pr.AssignedReviewers[0]
+
+	t.Run("LatestVerdictWins", func(t *testing.T) {
+		if _, _, err := svc.SubmitReview(t.Context(), "pr-1", reviewer, model.VerdictChangesRequested, nil); err != nil {
+			t.Fatalf("Failed to submit review: %v", err)
+		}
+		pr, review, err := svc.SubmitReview(t.Context(), "pr-1", reviewer, model.VerdictApproved, nil)
+		if err != nil {
+			t.Fatalf("Failed to submit review: %v", err)
+		}
+		if review.Verdict != model.VerdictApproved {
+			t.Errorf("Expected stored verdict %s, got %s", model.VerdictApproved, review.Verdict)
+		}
+		if len(pr.Reviews) != 1 || pr.Reviews[0].Verdict != model.VerdictApproved {
+			t.Errorf("Expected a single APPROVED verdict, got %+v", pr.Reviews)
+		}
+	})
+
+	t.Run("Rejections", func(t *testing.T) {
+		cases := []struct {
+			name, prID, userID, verdict, want string
+		}{
+			{"UnknownVerdict", "pr-1", reviewer, "LGTM", model.ErrInvalidInput},
+			{"UnknownPR", "pr-404", reviewer, model.VerdictApproved, model.ErrNotFound},
+			{"NotAssigned", "pr-1", "u1", model.VerdictApproved, model.ErrNotAssigned},
+		}
+		for _, tc := range cases {
+			_, _, err := svc.SubmitReview(t.Context(), tc.prID, tc.userID, tc.verdict, nil)
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("%s: expected %s, got %v", tc.name, tc.want, err)
+			}
+		}
+	})
+
+	t.Run("ReassignedReviewerVerdictDropped", func(t *testing.T) {
+		pr, _, err := svc.ReassignReviewer(t.Context(), "pr-1", reviewer, "")
+		if err != nil {
+			t.Fatalf("Failed to reassign: %v", err)
+		}
+		if len(pr.Reviews) != 0 {
+			t.Errorf("Expected no verdicts after reassignment, got %+v", pr.Reviews)
+		}
+	})
+
+	t.Run("MergedPR", func(t *testing.T) {
+		pr, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{})
+		if err != nil {
+			t.Fatalf("Failed to merge: %v", err)
+		}
+		_, _, err = svc.SubmitReview(t.Context(), "pr-1", pr.AssignedReviewers[0], model.VerdictApproved, nil)
+		if err == nil || err.Error() != model.ErrPRMerged {
+			t.Errorf("Expected %s, got %v", model.ErrPRMerged, err)
+		}
+	})
+}
+
+func TestMergeApprovalGate(t *testing.T) {
+	team := testTeam("backend", "u1", "u2", "u3")
+	team.ApprovalsRequired = 2
+	svc := newTestService(t, team)
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	first, second := pr.AssignedReviewers[0], pr.AssignedReviewers[1]
+
+	if _, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{}); err == nil || err.Error() != model.ErrNotApproved {
+		t.Fatalf("Expected %s without approvals, got %v", model.ErrNotApproved, err)
+	}
+
+	svc.SubmitReview(t.Context(), "pr-1", first, model.VerdictApproved, nil)
+	svc.SubmitReview(t.Context(), "pr-1", second, model.VerdictChangesRequested, nil)
+	if _, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{}); err == nil || err.Error() != model.ErrNotApproved {
+		t.Fatalf("Expected %s with outstanding changes requested, got %v", model.ErrNotApproved, err)
+	}
+
+	t.Run("ForceRequiresActor", func(t *testing.T) {
+		_, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{Force: true})
+		if err == nil || err.Error() != model.ErrInvalidInput {
+			t.Errorf("Expected %s, got %v", model.ErrInvalidInput, err)
+		}
+	})
+
+	t.Run("ApprovedMergesWithoutAudit", func(t *testing.T) {
+		svc.SubmitReview(t.Context(), "pr-1", second, model.VerdictApproved, nil)
+		pr, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{Force: true, ForcedBy: "u1"})
+		if err != nil {
+			t.Fatalf("Failed to merge: %v", err)
+		}
+		if pr.Status != model.StatusMerged {
+			t.Errorf("Expected MERGED, got %s", pr.Status)
+		}
+		overrides, _ := svc.ListMergeOverrides(t.Context(), 10)
+		if len(overrides) != 0 {
+			t.Errorf("Expected no override when the quorum is met, got %+v", overrides)
+		}
+	})
+
+	t.Run("ForcedMergeIsAudited", func(t *testing.T) {
+		if _, err := svc.CreatePR(t.Context(), "pr-2", "Hotfix", "u1", CreatePROptions{}); err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+		if _, err := svc.MergePR(t.Context(), "pr-2", MergeOptions{Force: true, ForcedBy: "u1", Reason: "incident"}); err != nil {
+			t.Fatalf("Failed to force merge: %v", err)
+		}
+		overrides, _ := svc.ListMergeOverrides(t.Context(), 10)
+		if len(overrides) != 1 {
+			t.Fatalf("Expected 1 override, got %d", len(overrides))
+		}
+		o := overrides[0]
+		if o.PullRequestID != "pr-2" || o.ForcedBy != "u1" || o.Reason != "incident" || o.Approvals != 0 || o.ApprovalsRequired != 2 {
+			t.Errorf("Unexpected override %+v", o)
+		}
+	})
+}
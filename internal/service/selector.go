@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/storage"
+)
+
+// ReviewerSelector picks up to count reviewers out of candidates for a PR
+// owned by teamName. Implementations must be safe for concurrent use.
+type ReviewerSelector interface {
+	Select(ctx context.Context, teamName string, candidates []model.User, count int) ([]string, error)
+}
+
+// randomSelector picks reviewers uniformly at random.
+type randomSelector struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newRandomSelector(rng *rand.Rand) *randomSelector {
+	return &randomSelector{rng: rng}
+}
+
+func (r *randomSelector) Select(_ context.Context, _ string, candidates []model.User, count int) ([]string, error) {
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	r.mu.Lock()
+	indices := r.rng.Perm(len(candidates))
+	r.mu.Unlock()
+
+	reviewers := make([]string, count)
+	for i := 0; i < count; i++ {
+		reviewers[i] = candidates[indices[i]].UserID
+	}
+	return reviewers, nil
+}
+
+// roundRobinSelector walks the candidates in user_id order, resuming after
+// whichever of them was most recently given a review. The cursor is derived
+// from the reviewer history rather than kept in memory, so it survives
+// restarts, is shared by all replicas and is naturally separate for each
+// candidate list (team, CODEOWNERS, fallback pool).
+type roundRobinSelector struct {
+	store storage.Repository
+}
+
+func newRoundRobinSelector(store storage.Repository) *roundRobinSelector {
+	return &roundRobinSelector{store: store}
+}
+
+func (r *roundRobinSelector) Select(ctx context.Context, _ string, candidates []model.User, count int) ([]string, error) {
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	if count == 0 {
+		return []string{}, nil
+	}
+
+	ordered := append([]model.User{}, candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	userIDs := make([]string, len(ordered))
+	for i, c := range ordered {
+		userIDs[i] = c.UserID
+	}
+	last, err := r.store.GetLastAssignedReviewer(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	start := sort.SearchStrings(userIDs, last)
+	if last != "" {
+		start++
+	}
+
+	reviewers := make([]string, count)
+	for i := 0; i < count; i++ {
+		reviewers[i] = userIDs[(start+i)%len(userIDs)]
+	}
+	return reviewers, nil
+}
+
+// leastLoadedSelector prefers candidates with the fewest open reviews,
+// breaking ties randomly.
+type leastLoadedSelector struct {
+	store storage.Repository
+	mu    sync.Mutex
+	rng   *rand.Rand
+}
+
+func newLeastLoadedSelector(store storage.Repository, rng *rand.Rand) *leastLoadedSelector {
+	return &leastLoadedSelector{store: store, rng: rng}
+}
+
+func (l *leastLoadedSelector) Select(ctx context.Context, _ string, candidates []model.User, count int) ([]string, error) {
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	userIDs := make([]string, len(candidates))
+	for i, c := range candidates {
+		userIDs[i] = c.UserID
+	}
+	load, err := l.store.GetOpenReviewCounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	l.rng.Shuffle(len(userIDs), func(i, j int) {
+		userIDs[i], userIDs[j] = userIDs[j], userIDs[i]
+	})
+	l.mu.Unlock()
+
+	sort.SliceStable(userIDs, func(i, j int) bool {
+		return load[userIDs[i]] < load[userIDs[j]]
+	})
+	return userIDs[:count], nil
+}
+
+func isValidStrategy(strategy string) bool {
+	switch strategy {
+	case model.StrategyRandom, model.StrategyRoundRobin, model.StrategyLeastLoaded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestRoundRobinStrategy(t *testing.T) {
+	team := testTeam("backend", "u0", "u1", "u2", "u3")
+	team.ReviewerStrategy = model.StrategyRoundRobin
+	svc := newTestService(t, team)
+
+	expected := [][]string{{"u1", "u2"}, {"u3", "u1"}, {"u2", "u3"}}
+	for i, want := range expected {
+		// A fresh service stands in for a restart or another replica: the
+		// rotation must carry on from the stored history.
+		svc = New(svc.store)
+		pr, err := svc.CreatePR(t.Context(), fmt.Sprintf("pr-%d", i), "Change", "u0", CreatePROptions{})
+		if err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+		if !reflect.DeepEqual(pr.AssignedReviewers, want) {
+			t.Errorf("Round %d: expected %v, got %v", i, want, pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestRoundRobinSelectorCursorPerCandidateList(t *testing.T) {
+	team := testTeam("backend", "u0", "u1", "u2", "u3")
+	team.ReviewerStrategy = model.StrategyRoundRobin
+	svc := newTestService(t, team)
+
+	// The team rotation hands out u1, u2, then u3, u1.
+	for _, prID := range []string{"pr-1", "pr-2"} {
+		if _, err := svc.CreatePR(t.Context(), prID, "Change", "u0", CreatePROptions{}); err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+	}
+
+	// Of u2..u4, u3 was picked last, so the next one is u4 even though the
+	// team's own cursor is at u1.
+	sel := newRoundRobinSelector(svc.store)
+	got, err := sel.Select(t.Context(), "backend", []model.User{{UserID: "u2"}, {UserID: "u3"}, {UserID: "u4"}}, 1)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"u4"}) {
+		t.Errorf("Expected u4, got %v", got)
+	}
+}
+
+func TestLeastLoadedStrategy(t *testing.T) {
+	team := testTeam("backend", "u1", "u2", "u3", "u4")
+	team.ReviewerStrategy = model.StrategyLeastLoaded
+	svc := newTestService(t, team)
+
+	first, err := svc.CreatePR(t.Context(), "pr-1", "First", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+
+	// Two of u2..u4 now have an open review; the idle one must be picked.
+	busy := map[string]bool{}
+	for _, id := range first.AssignedReviewers {
+		busy[id] = true
+	}
+
+	second, err := svc.CreatePR(t.Context(), "pr-2", "Second", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	idle := 0
+	for _, id := range second.AssignedReviewers {
+		if !busy[id] {
+			idle++
+		}
+	}
+	if idle != 1 {
+		t.Errorf("Expected the only idle member to be picked, got %v (busy %v)", second.AssignedReviewers, first.AssignedReviewers)
+	}
+}
+
+func TestSetReviewerStrategy(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2"))
+
+	team, err := svc.SetReviewerStrategy(t.Context(), "backend", model.StrategyRoundRobin)
+	if err != nil {
+		t.Fatalf("Failed to set strategy: %v", err)
+	}
+	if team.ReviewerStrategy != model.StrategyRoundRobin {
+		t.Errorf("Expected %s, got %s", model.StrategyRoundRobin, team.ReviewerStrategy)
+	}
+
+	if _, err := svc.SetReviewerStrategy(t.Context(), "backend", "senior_first"); err == nil || err.Error() != model.ErrInvalidInput {
+		t.Errorf("Expected %s, got %v", model.ErrInvalidInput, err)
+	}
+	if _, err := svc.SetReviewerStrategy(t.Context(), "missing", model.StrategyRandom); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s, got %v", model.ErrNotFound, err)
+	}
+}
+
+func TestReviewCapacity(t *testing.T) {
+	team := testTeam("backend", "u1", "u2", "u3")
+	team.ReviewersRequired = 1
+	team.MaxOpenReviews = 1
+	svc := newTestService(t, team)
+
+	// u1 authors everything; u2 and u3 can each hold one open review.
+	first, err := svc.CreatePR(t.Context(), "pr-1", "First", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	second, err := svc.CreatePR(t.Context(), "pr-2", "Second", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if first.AssignedReviewers[0] == second.AssignedReviewers[0] {
+		t.Fatalf("Expected the reviewer at capacity to be skipped, both got %v", first.AssignedReviewers)
+	}
+
+	_, err = svc.CreatePR(t.Context(), "pr-3", "Third", "u1", CreatePROptions{})
+	if err == nil || err.Error() != model.ErrNoCandidate {
+		t.Fatalf("Expected %s when everyone is at capacity, got %v", model.ErrNoCandidate, err)
+	}
+
+	t.Run("PersonalLimitOverridesTeam", func(t *testing.T) {
+		limit := 2
+		if _, err := svc.SetUserMaxOpenReviews(t.Context(), "u2", &limit); err != nil {
+			t.Fatalf("Failed to set limit: %v", err)
+		}
+		pr, err := svc.CreatePR(t.Context(), "pr-3", "Third", "u1", CreatePROptions{})
+		if err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+		if !reflect.DeepEqual(pr.AssignedReviewers, []string{"u2"}) {
+			t.Errorf("Expected u2 with the higher personal limit, got %v", pr.AssignedReviewers)
+		}
+	})
+
+	t.Run("LeastLoadedOverflow", func(t *testing.T) {
+		overflow := model.CapacityLeastLoaded
+		if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{CapacityOverflow: &overflow}); err != nil {
+			t.Fatalf("Failed to update team: %v", err)
+		}
+		pr, err := svc.CreatePR(t.Context(), "pr-4", "Fourth", "u1", CreatePROptions{})
+		if err != nil {
+			t.Fatalf("Expected overflow assignment, got %v", err)
+		}
+		if !reflect.DeepEqual(pr.AssignedReviewers, []string{"u3"}) {
+			t.Errorf("Expected the least loaded u3, got %v", pr.AssignedReviewers)
+		}
+	})
+
+	t.Run("InvalidSettings", func(t *testing.T) {
+		negative := -1
+		if _, err := svc.SetUserMaxOpenReviews(t.Context(), "u2", &negative); err == nil || err.Error() != model.ErrInvalidInput {
+			t.Errorf("Expected %s, got %v", model.ErrInvalidInput, err)
+		}
+		overflow := "queue"
+		if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{CapacityOverflow: &overflow}); err == nil || err.Error() != model.ErrInvalidInput {
+			t.Errorf("Expected %s, got %v", model.ErrInvalidInput, err)
+		}
+	})
+}
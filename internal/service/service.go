@@ -1,28 +1,44 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"log/slog"
 	"math/rand"
 	"time"
 
+	"pr-reviewer-service/internal/metrics"
 	"pr-reviewer-service/internal/model"
 	"pr-reviewer-service/internal/storage"
+	"pr-reviewer-service/internal/tracing"
 )
 
 type Service struct {
-	store *storage.Storage
-	rng   *rand.Rand
+	store     storage.Repository
+	selectors map[string]ReviewerSelector
+	now       func() time.Time
 }
 
-func New(store *storage.Storage) *Service {
+func New(store storage.Repository) *Service {
+	seed := time.Now().UnixNano()
 	return &Service{
 		store: store,
-		rng:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		selectors: map[string]ReviewerSelector{
+			model.StrategyRandom:      newRandomSelector(rand.New(rand.NewSource(seed))),
+			model.StrategyRoundRobin:  newRoundRobinSelector(store),
+			model.StrategyLeastLoaded: newLeastLoadedSelector(store, rand.New(rand.NewSource(seed+1))),
+		},
+		now: time.Now,
 	}
 }
 
-func (s *Service) CreateTeam(team model.Team) (*model.Team, error) {
-	exists, err := s.store.TeamExists(team.TeamName)
+func (s *Service) CreateTeam(ctx context.Context, team model.Team) (*model.Team, error) {
+	ctx, span := tracing.Start(ctx, "service.CreateTeam")
+	defer span.End()
+
+	exists, err := s.store.TeamExists(ctx, team.TeamName)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +46,38 @@ func (s *Service) CreateTeam(team model.Team) (*model.Team, error) {
 		return nil, errors.New(model.ErrTeamExists)
 	}
 
-	if err := s.store.CreateTeam(team.TeamName); err != nil {
+	if team.ReviewerStrategy == "" {
+		team.ReviewerStrategy = model.StrategyRandom
+	}
+	if team.ReviewersRequired == 0 {
+		team.ReviewersRequired = model.DefaultReviewersRequired
+	}
+	if team.MaxReviewers == 0 {
+		team.MaxReviewers = team.ReviewersRequired
+	}
+	if team.CapacityOverflow == "" {
+		team.CapacityOverflow = model.CapacityReject
+	}
+	if team.CodeOwnersMode == "" {
+		team.CodeOwnersMode = model.CodeOwnersPrefer
+	}
+	if team.UnderstaffedPolicy == "" {
+		team.UnderstaffedPolicy = model.UnderstaffedWarn
+	}
+	if team.SLAAction == "" {
+		team.SLAAction = model.SLAEscalate
+	}
+	if err := validateTeamSettings(team.TeamSettings); err != nil {
+		return nil, err
+	}
+	if err := s.checkFallbackPools(ctx, team.FallbackPools); err != nil {
+		return nil, err
+	}
+	if err := s.checkTeamLead(ctx, team.TeamLeadID, team.Members); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.CreateTeam(ctx, team.TeamName, team.TeamSettings); err != nil {
 		return nil, err
 	}
 
@@ -41,16 +88,19 @@ func (s *Service) CreateTeam(team model.Team) (*model.Team, error) {
 			TeamName: team.TeamName,
 			IsActive: member.IsActive,
 		}
-		if err := s.store.UpsertUser(user); err != nil {
+		if err := s.store.UpsertUser(ctx, user); err != nil {
 			return nil, err
 		}
 	}
 
-	return s.store.GetTeam(team.TeamName)
+	return s.store.GetTeam(ctx, team.TeamName)
 }
 
-func (s *Service) GetTeam(teamName string) (*model.Team, error) {
-	team, err := s.store.GetTeam(teamName)
+func (s *Service) GetTeam(ctx context.Context, teamName string) (*model.Team, error) {
+	ctx, span := tracing.Start(ctx, "service.GetTeam")
+	defer span.End()
+
+	team, err := s.store.GetTeam(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -60,16 +110,165 @@ func (s *Service) GetTeam(teamName string) (*model.Team, error) {
 	return team, nil
 }
 
-func (s *Service) SetUserActive(userID string, isActive bool) (*model.User, error) {
-	err := s.store.SetUserActive(userID, isActive)
+func (s *Service) UpdateTeam(ctx context.Context, teamName string, update model.TeamSettingsUpdate) (*model.Team, error) {
+	ctx, span := tracing.Start(ctx, "service.UpdateTeam")
+	defer span.End()
+
+	settings, err := s.store.GetTeamSettings(ctx, teamName)
 	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
 		return nil, errors.New(model.ErrNotFound)
 	}
-	return s.store.GetUser(userID)
+
+	if update.ReviewerStrategy != nil {
+		settings.ReviewerStrategy = *update.ReviewerStrategy
+	}
+	if update.ReviewersRequired != nil {
+		settings.ReviewersRequired = *update.ReviewersRequired
+		if update.MaxReviewers == nil && settings.MaxReviewers < settings.ReviewersRequired {
+			settings.MaxReviewers = settings.ReviewersRequired
+		}
+	}
+	if update.MaxReviewers != nil {
+		settings.MaxReviewers = *update.MaxReviewers
+	}
+	if update.ApprovalsRequired != nil {
+		settings.ApprovalsRequired = *update.ApprovalsRequired
+	}
+	if update.MaxOpenReviews != nil {
+		settings.MaxOpenReviews = *update.MaxOpenReviews
+	}
+	if update.CapacityOverflow != nil {
+		settings.CapacityOverflow = *update.CapacityOverflow
+	}
+	if update.CodeOwnersMode != nil {
+		settings.CodeOwnersMode = *update.CodeOwnersMode
+	}
+	if update.UnderstaffedPolicy != nil {
+		settings.UnderstaffedPolicy = *update.UnderstaffedPolicy
+	}
+	if update.ReviewSLAMinutes != nil {
+		settings.ReviewSLAMinutes = *update.ReviewSLAMinutes
+	}
+	if update.SLAAction != nil {
+		settings.SLAAction = *update.SLAAction
+	}
+	if update.TeamLeadID != nil {
+		settings.TeamLeadID = *update.TeamLeadID
+		if err := s.checkTeamLead(ctx, settings.TeamLeadID, nil); err != nil {
+			return nil, err
+		}
+	}
+	if update.FallbackPools != nil {
+		settings.FallbackPools = *update.FallbackPools
+		if err := s.checkFallbackPools(ctx, settings.FallbackPools); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateTeamSettings(*settings); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.UpdateTeamSettings(ctx, teamName, *settings); err != nil {
+		return nil, err
+	}
+	return s.store.GetTeam(ctx, teamName)
+}
+
+func (s *Service) SetReviewerStrategy(ctx context.Context, teamName, strategy string) (*model.Team, error) {
+	ctx, span := tracing.Start(ctx, "service.SetReviewerStrategy")
+	defer span.End()
+
+	return s.UpdateTeam(ctx, teamName, model.TeamSettingsUpdate{ReviewerStrategy: &strategy})
+}
+
+func validateTeamSettings(settings model.TeamSettings) error {
+	if !isValidStrategy(settings.ReviewerStrategy) {
+		return model.NewError(model.ErrInvalidInput, "unknown reviewer_strategy")
+	}
+	if settings.ReviewersRequired < 1 {
+		return model.NewError(model.ErrInvalidInput, "reviewers_required must be at least 1")
+	}
+	if settings.MaxReviewers < settings.ReviewersRequired {
+		return model.NewError(model.ErrInvalidInput, "max_reviewers must not be less than reviewers_required")
+	}
+	if settings.ApprovalsRequired < 0 || settings.ApprovalsRequired > settings.MaxReviewers {
+		return model.NewError(model.ErrInvalidInput, "approvals_required must be between 0 and max_reviewers")
+	}
+	if settings.MaxOpenReviews < 0 {
+		return model.NewError(model.ErrInvalidInput, "max_open_reviews must not be negative")
+	}
+	if settings.CapacityOverflow != model.CapacityReject && settings.CapacityOverflow != model.CapacityLeastLoaded {
+		return model.NewError(model.ErrInvalidInput, "capacity_overflow must be reject or least_loaded")
+	}
+	if settings.CodeOwnersMode != model.CodeOwnersPrefer && settings.CodeOwnersMode != model.CodeOwnersRequire {
+		return model.NewError(model.ErrInvalidInput, "code_owners_mode must be prefer or require")
+	}
+	switch settings.UnderstaffedPolicy {
+	case model.UnderstaffedAllow, model.UnderstaffedWarn, model.UnderstaffedReject:
+	default:
+		return model.NewError(model.ErrInvalidInput, "understaffed_policy must be allow, warn or reject")
+	}
+	if settings.ReviewSLAMinutes < 0 {
+		return model.NewError(model.ErrInvalidInput, "review_sla_minutes must not be negative")
+	}
+	if settings.SLAAction != model.SLAEscalate && settings.SLAAction != model.SLAReassign {
+		return model.NewError(model.ErrInvalidInput, "sla_action must be escalate or reassign")
+	}
+	return nil
 }
 
-func (s *Service) CreatePR(prID, prName, authorID string) (*model.PullRequest, error) {
-	exists, err := s.store.PRExists(prID)
+func (s *Service) SetUserActive(ctx context.Context, userID string, isActive bool) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "service.SetUserActive")
+	defer span.End()
+
+	err := s.store.SetUserActive(ctx, userID, isActive)
+	if err != nil {
+		return nil, errors.New(model.ErrNotFound)
+	}
+	return s.store.GetUser(ctx, userID)
+}
+
+// SetUserMaxOpenReviews sets a personal open review limit for userID. A nil
+// limit falls back to the team's max_open_reviews; zero means unlimited.
+func (s *Service) SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "service.SetUserMaxOpenReviews")
+	defer span.End()
+
+	if limit != nil && *limit < 0 {
+		return nil, model.NewError(model.ErrInvalidInput, "max_open_reviews must not be negative")
+	}
+
+	err := s.store.SetUserMaxOpenReviews(ctx, userID, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.store.GetUser(ctx, userID)
+}
+
+// CreatePROptions carries optional per-PR overrides for CreatePR.
+type CreatePROptions struct {
+	// ReviewersRequired overrides the team's reviewer count when non-zero.
+	// It must lie between the team's reviewers_required and max_reviewers.
+	ReviewersRequired int
+	// Draft creates the PR as DRAFT without reviewers; they are assigned
+	// when it is marked ready for review.
+	Draft bool
+	// ChangedFiles are the repository paths the PR touches. They are matched
+	// against the team's CODEOWNERS rules and stored with the PR.
+	ChangedFiles []string
+}
+
+func (s *Service) CreatePR(ctx context.Context, prID, prName, authorID string, opts CreatePROptions) (*model.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "service.CreatePR")
+	defer span.End()
+
+	exists, err := s.store.PRExists(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +276,7 @@ func (s *Service) CreatePR(prID, prName, authorID string) (*model.PullRequest, e
 		return nil, errors.New(model.ErrPRExists)
 	}
 
-	author, err := s.store.GetUser(authorID)
+	author, err := s.store.GetUser(ctx, authorID)
 	if err != nil {
 		return nil, err
 	}
@@ -85,30 +284,196 @@ func (s *Service) CreatePR(prID, prName, authorID string) (*model.PullRequest, e
 		return nil, errors.New(model.ErrNotFound)
 	}
 
-	activeMembers, err := s.store.GetActiveTeamMembers(author.TeamName, authorID)
+	files, err := normalizeChangedFiles(opts.ChangedFiles)
 	if err != nil {
 		return nil, err
 	}
 
-	reviewers := s.selectRandomReviewers(activeMembers, 2)
-
 	pr := model.PullRequest{
 		PullRequestID:     prID,
 		PullRequestName:   prName,
 		AuthorID:          authorID,
 		Status:            model.StatusOpen,
-		AssignedReviewers: reviewers,
+		AssignedReviewers: []string{},
+		ChangedFiles:      files,
+	}
+
+	if opts.Draft {
+		if opts.ReviewersRequired != 0 {
+			return nil, model.NewError(model.ErrInvalidInput,
+				"reviewers_required is applied when the draft is marked ready")
+		}
+		pr.Status = model.StatusDraft
+	} else {
+		pr.ReviewerSources, pr.ReviewersRequired, err = s.pickReviewers(ctx, author, opts.ReviewersRequired, files)
+		if err != nil {
+			return nil, err
+		}
+		pr.AssignedReviewers = reviewerIDs(pr.ReviewerSources)
 	}
 
-	if err := s.store.CreatePR(pr); err != nil {
+	events := assignedEvents(&pr, pr.ReviewerSources)
+	if err := s.store.CreatePR(ctx, pr, events...); err != nil {
 		return nil, err
 	}
+	metrics.Assigned(model.AssignmentAssigned, model.ReasonAuto, len(pr.ReviewerSources))
+	slog.InfoContext(ctx, "Pull request created",
+		"pull_request_id", prID, "status", pr.Status, "reviewers", pr.AssignedReviewers)
+
+	return s.store.GetPR(ctx, prID)
+}
+
+// pickReviewers selects reviewers for a PR by author. override is the
+// optional per-PR reviewer count. Candidates are drawn in order from:
+//   - the owners of files under the team's CODEOWNERS rules; in
+//     CodeOwnersRequire mode at least one of them must be picked,
+//   - the author's active teammates,
+//   - the team's fallback pools, while slots remain unfilled.
+//
+// Each reviewer is returned with the source it was picked from, together
+// with the resolved reviewer count. If fewer reviewers than that are found
+// and the team's understaffed_policy is reject, ErrUnderstaffed is returned.
+func (s *Service) pickReviewers(ctx context.Context, author *model.User, override int, files []string) ([]model.ReviewerSource, int, error) {
+	reviewerCount, err := s.reviewerCount(ctx, author.TeamName, override)
+	if err != nil {
+		return nil, 0, err
+	}
+	settings, err := s.store.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	picked := []model.ReviewerSource{}
+	// noCandidate keeps the first capacity error so that it can be reported
+	// if no later source fills a slot either.
+	var noCandidate error
+	take := func(source, name string, candidates []model.User) error {
+		remaining := reviewerCount - len(picked)
+		if remaining <= 0 {
+			return nil
+		}
+		userIDs, err := s.selectReviewers(ctx, author.TeamName, excludeUsers(candidates, reviewerIDs(picked)), remaining)
+		if err != nil {
+			if err.Error() != model.ErrNoCandidate {
+				return err
+			}
+			if noCandidate == nil {
+				noCandidate = err
+			}
+			return nil
+		}
+		for _, userID := range userIDs {
+			picked = append(picked, model.ReviewerSource{UserID: userID, Source: source, Name: name})
+		}
+		return nil
+	}
+
+	owners, matched, err := s.codeOwnerCandidates(ctx, author.TeamName, author, files)
+	if err != nil {
+		return nil, 0, err
+	}
+	if matched {
+		if err := take(model.SourceCodeOwners, author.TeamName, owners); err != nil {
+			return nil, 0, err
+		}
+		if len(picked) == 0 && settings != nil && settings.CodeOwnersMode == model.CodeOwnersRequire {
+			noCandidateFound(ctx, author.TeamName)
+			return nil, 0, noCodeOwnerError(author.TeamName)
+		}
+	}
+
+	activeMembers, err := s.store.GetActiveTeamMembers(ctx, author.TeamName, author.UserID, s.now())
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := take(model.SourceTeam, author.TeamName, activeMembers); err != nil {
+		return nil, 0, err
+	}
+
+	if settings != nil {
+		for _, poolName := range settings.FallbackPools {
+			if len(picked) >= reviewerCount {
+				break
+			}
+			members, err := s.store.GetActivePoolMembers(ctx, poolName, author.UserID, s.now())
+			if err != nil {
+				return nil, 0, err
+			}
+			if err := take(model.SourcePool, poolName, members); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	if len(picked) == 0 && noCandidate != nil {
+		noCandidateFound(ctx, author.TeamName)
+		return nil, 0, noCandidate
+	}
+	if len(picked) < reviewerCount && settings != nil && settings.UnderstaffedPolicy == model.UnderstaffedReject {
+		return nil, 0, model.NewError(model.ErrUnderstaffed, understaffedMessage(len(picked), reviewerCount))
+	}
+	return picked, reviewerCount, nil
+}
+
+func reviewerIDs(reviewers []model.ReviewerSource) []string {
+	userIDs := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		userIDs = append(userIDs, r.UserID)
+	}
+	return userIDs
+}
+
+func assignedEvents(pr *model.PullRequest, reviewers []model.ReviewerSource) []model.Event {
+	events := make([]model.Event, 0, len(reviewers))
+	for _, r := range reviewers {
+		events = append(events, model.NewEvent(model.EventReviewerAssigned, map[string]interface{}{
+			"pull_request_id":   pr.PullRequestID,
+			"pull_request_name": pr.PullRequestName,
+			"author_id":         pr.AuthorID,
+			"reviewer_id":       r.UserID,
+			"source":            r.Source,
+			"source_name":       r.Name,
+		}))
+	}
+	return events
+}
+
+// reviewerCount resolves how many reviewers a new PR of teamName needs,
+// applying the optional per-PR override within the team's bounds.
+func (s *Service) reviewerCount(ctx context.Context, teamName string, override int) (int, error) {
+	settings, err := s.store.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return 0, err
+	}
+	if settings == nil {
+		return model.DefaultReviewersRequired, nil
+	}
+
+	if override == 0 {
+		return settings.ReviewersRequired, nil
+	}
+	if override < settings.ReviewersRequired || override > settings.MaxReviewers {
+		return 0, model.NewError(model.ErrInvalidInput, fmt.Sprintf(
+			"reviewers_required must be between %d and %d for team %s",
+			settings.ReviewersRequired, settings.MaxReviewers, teamName))
+	}
+	return override, nil
+}
 
-	return s.store.GetPR(prID)
+// MergeOptions controls how MergePR treats the team's approval policy.
+type MergeOptions struct {
+	// Force merges even if the approval requirement is not met. The bypass is
+	// recorded as a model.MergeOverride attributed to ForcedBy.
+	Force    bool
+	ForcedBy string
+	Reason   string
 }
 
-func (s *Service) MergePR(prID string) (*model.PullRequest, error) {
-	pr, err := s.store.GetPR(prID)
+func (s *Service) MergePR(ctx context.Context, prID string, opts MergeOptions) (*model.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "service.MergePR")
+	defer span.End()
+
+	pr, err := s.store.GetPR(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -119,16 +484,115 @@ func (s *Service) MergePR(prID string) (*model.PullRequest, error) {
 	if pr.Status == model.StatusMerged {
 		return pr, nil
 	}
+	if err := checkTransition(pr.Status, model.StatusMerged); err != nil {
+		return nil, err
+	}
 
-	if err := s.store.MergePR(prID); err != nil {
+	if opts.Force && opts.ForcedBy == "" {
+		return nil, model.NewError(model.ErrInvalidInput, "forced_by is required for a forced merge")
+	}
+
+	var override *model.MergeOverride
+	required, err := s.approvalsRequired(ctx, pr)
+	if err != nil {
 		return nil, err
 	}
+	if gateErr := checkApprovals(pr, required); gateErr != nil {
+		if !opts.Force {
+			return nil, gateErr
+		}
+		override = &model.MergeOverride{
+			PullRequestID:     prID,
+			ForcedBy:          opts.ForcedBy,
+			Reason:            opts.Reason,
+			Approvals:         countVerdicts(pr, model.VerdictApproved),
+			ApprovalsRequired: required,
+		}
+	}
 
-	return s.store.GetPR(prID)
+	return s.merge(ctx, pr, override)
 }
 
-func (s *Service) ReassignReviewer(prID, oldUserID string) (*model.PullRequest, string, error) {
-	pr, err := s.store.GetPR(prID)
+// merge records the merge of pr without consulting the approval policy.
+func (s *Service) merge(ctx context.Context, pr *model.PullRequest, override *model.MergeOverride) (*model.PullRequest, error) {
+	data := map[string]interface{}{
+		"pull_request_id":    pr.PullRequestID,
+		"pull_request_name":  pr.PullRequestName,
+		"author_id":          pr.AuthorID,
+		"assigned_reviewers": pr.AssignedReviewers,
+		"forced":             override != nil,
+	}
+	if override != nil {
+		data["forced_by"] = override.ForcedBy
+	}
+	event := model.NewEvent(model.EventPRMerged, data)
+	err := s.store.MergePR(ctx, pr.PullRequestID, override, event)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewError(model.ErrInvalidTransition, "PR status changed concurrently")
+	}
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Pull request merged", "pull_request_id", pr.PullRequestID, "forced", override != nil)
+
+	return s.store.GetPR(ctx, pr.PullRequestID)
+}
+
+// approvalsRequired returns the approval quorum of the PR author's team.
+func (s *Service) approvalsRequired(ctx context.Context, pr *model.PullRequest) (int, error) {
+	author, err := s.store.GetUser(ctx, pr.AuthorID)
+	if err != nil || author == nil {
+		return 0, err
+	}
+	settings, err := s.store.GetTeamSettings(ctx, author.TeamName)
+	if err != nil || settings == nil {
+		return 0, err
+	}
+	return settings.ApprovalsRequired, nil
+}
+
+// checkApprovals returns ErrNotApproved unless at least required assigned
+// reviewers approved and none of them currently requests changes.
+func checkApprovals(pr *model.PullRequest, required int) error {
+	if required == 0 {
+		return nil
+	}
+	if n := countVerdicts(pr, model.VerdictChangesRequested); n > 0 {
+		return model.NewError(model.ErrNotApproved, fmt.Sprintf(
+			"%d reviewer(s) requested changes", n))
+	}
+	if n := countVerdicts(pr, model.VerdictApproved); n < required {
+		return model.NewError(model.ErrNotApproved, fmt.Sprintf(
+			"%d of %d required approvals", n, required))
+	}
+	return nil
+}
+
+func countVerdicts(pr *model.PullRequest, verdict string) int {
+	n := 0
+	for _, v := range pr.Reviews {
+		if v.Verdict == verdict {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Service) ListMergeOverrides(ctx context.Context, limit int) ([]model.MergeOverride, error) {
+	ctx, span := tracing.Start(ctx, "service.ListMergeOverrides")
+	defer span.End()
+
+	return s.store.ListMergeOverrides(ctx, limit)
+}
+
+// ReassignReviewer replaces oldUserID on the PR with another reviewer drawn
+// from the author's team or its fallback pools, and returns the PR with the
+// new reviewer's ID. actorID identifies who asked for it and may be empty.
+func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID, actorID string) (*model.PullRequest, string, error) {
+	ctx, span := tracing.Start(ctx, "service.ReassignReviewer")
+	defer span.End()
+
+	pr, err := s.store.GetPR(ctx, prID)
 	if err != nil {
 		return nil, "", err
 	}
@@ -136,88 +600,182 @@ func (s *Service) ReassignReviewer(prID, oldUserID string) (*model.PullRequest,
 		return nil, "", errors.New(model.ErrNotFound)
 	}
 
-	if pr.Status == model.StatusMerged {
-		return nil, "", errors.New(model.ErrPRMerged)
+	if err := requireOpen(pr); err != nil {
+		return nil, "", err
 	}
 
-	found := false
-	for _, reviewerID := range pr.AssignedReviewers {
-		if reviewerID == oldUserID {
-			found = true
-			break
-		}
-	}
-	if !found {
+	if !isAssigned(pr, oldUserID) {
 		return nil, "", errors.New(model.ErrNotAssigned)
 	}
 
-	oldUser, err := s.store.GetUser(oldUserID)
+	newReviewer, err := s.replacementFor(ctx, pr, oldUserID)
 	if err != nil {
 		return nil, "", err
 	}
-	if oldUser == nil {
-		return nil, "", errors.New(model.ErrNotFound)
+
+	if err := s.reassign(ctx, prID, oldUserID, newReviewer, model.ReasonManual, actorID); err != nil {
+		return nil, "", err
 	}
 
-	excludeUsers := map[string]bool{oldUserID: true, pr.AuthorID: true}
-	for _, reviewerID := range pr.AssignedReviewers {
-		excludeUsers[reviewerID] = true
+	pr, err = s.store.GetPR(ctx, prID)
+	return pr, newReviewer.UserID, err
+}
+
+func (s *Service) GetUserReviews(ctx context.Context, userID string) ([]model.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "service.GetUserReviews")
+	defer span.End()
+
+	return s.store.GetPRsByReviewer(ctx, userID)
+}
+
+// selectReviewers picks up to maxCount reviewers from users using the
+// selection strategy configured for teamName.
+func (s *Service) selectReviewers(ctx context.Context, teamName string, users []model.User, maxCount int) ([]string, error) {
+	if len(users) == 0 || maxCount <= 0 {
+		return []string{}, nil
 	}
 
-	candidates, err := s.store.GetActiveTeamMembers(oldUser.TeamName, "")
+	settings, err := s.store.GetTeamSettings(ctx, teamName)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	availableCandidates := []model.User{}
-	for _, c := range candidates {
-		if !excludeUsers[c.UserID] {
-			availableCandidates = append(availableCandidates, c)
+	selector := s.selectors[model.StrategyRandom]
+	if settings != nil {
+		if sel, ok := s.selectors[settings.ReviewerStrategy]; ok {
+			selector = sel
 		}
 	}
 
-	if len(availableCandidates) == 0 {
-		return nil, "", errors.New(model.ErrNoCandidate)
+	available, full, err := s.splitByCapacity(ctx, settings, users)
+	if err != nil {
+		return nil, err
+	}
+	picked, err := selector.Select(ctx, teamName, available, maxCount)
+	if err != nil || len(full) == 0 || len(picked) > 0 {
+		return picked, err
 	}
 
-	newReviewer := availableCandidates[s.rng.Intn(len(availableCandidates))]
+	if settings == nil || settings.CapacityOverflow != model.CapacityLeastLoaded {
+		return nil, model.NewError(model.ErrNoCandidate, fmt.Sprintf(
+			"all %d candidate(s) in team %s are at their max_open_reviews", len(full), teamName))
+	}
+	return s.selectors[model.StrategyLeastLoaded].Select(ctx, teamName, full, maxCount)
+}
 
-	if err := s.store.ReassignReviewer(prID, oldUserID, newReviewer.UserID); err != nil {
-		return nil, "", err
+// splitByCapacity separates users who can take another review from those
+// who already have as many open reviews as their personal limit or, if
+// they have none, the team's max_open_reviews allows.
+func (s *Service) splitByCapacity(ctx context.Context, settings *model.TeamSettings, users []model.User) (available, full []model.User, err error) {
+	teamLimit := 0
+	if settings != nil {
+		teamLimit = settings.MaxOpenReviews
+	}
+
+	limits := make(map[string]int, len(users))
+	userIDs := []string{}
+	for _, u := range users {
+		limit := teamLimit
+		if u.MaxOpenReviews != nil {
+			limit = *u.MaxOpenReviews
+		}
+		if limit > 0 {
+			limits[u.UserID] = limit
+			userIDs = append(userIDs, u.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return users, nil, nil
 	}
 
-	pr, err = s.store.GetPR(prID)
-	return pr, newReviewer.UserID, err
+	load, err := s.store.GetOpenReviewCounts(ctx, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, u := range users {
+		if limit, ok := limits[u.UserID]; ok && load[u.UserID] >= limit {
+			full = append(full, u)
+		} else {
+			available = append(available, u)
+		}
+	}
+	return available, full, nil
 }
 
-func (s *Service) GetUserReviews(userID string) ([]model.PullRequestShort, error) {
-	return s.store.GetPRsByReviewer(userID)
-}
+// replacementFor picks a reviewer to take over oldUserID's review of pr the
+// way pickReviewers fills a slot: from the author's active teammates, then
+// from the author's team's fallback pools, using that team's strategy and
+// capacity settings. It returns ErrNoCandidate if nobody besides the author
+// and the current reviewers is left.
+func (s *Service) replacementFor(ctx context.Context, pr *model.PullRequest, oldUserID string) (model.ReviewerSource, error) {
+	author, err := s.store.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return model.ReviewerSource{}, err
+	}
+	if author == nil {
+		return model.ReviewerSource{}, errors.New(model.ErrNotFound)
+	}
+	settings, err := s.store.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return model.ReviewerSource{}, err
+	}
 
-func (s *Service) selectRandomReviewers(users []model.User, maxCount int) []string {
-	if len(users) == 0 {
-		return []string{}
+	exclude := append([]string{oldUserID}, pr.AssignedReviewers...)
+	// noCandidate keeps the first capacity error, as in pickReviewers.
+	var noCandidate error
+	take := func(source, name string, candidates []model.User) (model.ReviewerSource, error) {
+		picked, err := s.selectReviewers(ctx, author.TeamName, excludeUsers(candidates, exclude), 1)
+		if err != nil {
+			if err.Error() != model.ErrNoCandidate {
+				return model.ReviewerSource{}, err
+			}
+			if noCandidate == nil {
+				noCandidate = err
+			}
+			return model.ReviewerSource{}, nil
+		}
+		if len(picked) == 0 {
+			return model.ReviewerSource{}, nil
+		}
+		return model.ReviewerSource{UserID: picked[0], Source: source, Name: name}, nil
 	}
 
-	count := maxCount
-	if len(users) < count {
-		count = len(users)
+	members, err := s.store.GetActiveTeamMembers(ctx, author.TeamName, author.UserID, s.now())
+	if err != nil {
+		return model.ReviewerSource{}, err
+	}
+	if r, err := take(model.SourceTeam, author.TeamName, members); err != nil || r.UserID != "" {
+		return r, err
 	}
 
-	indices := s.rng.Perm(len(users))
-	reviewers := make([]string, count)
-	for i := 0; i < count; i++ {
-		reviewers[i] = users[indices[i]].UserID
+	if settings != nil {
+		for _, poolName := range settings.FallbackPools {
+			members, err := s.store.GetActivePoolMembers(ctx, poolName, author.UserID, s.now())
+			if err != nil {
+				return model.ReviewerSource{}, err
+			}
+			if r, err := take(model.SourcePool, poolName, members); err != nil || r.UserID != "" {
+				return r, err
+			}
+		}
 	}
-	return reviewers
-}
 
-func (s *Service) GetStatistics() (map[string]interface{}, error) {
-	return s.store.GetStatistics()
+	noCandidateFound(ctx, author.TeamName)
+	if noCandidate != nil {
+		return model.ReviewerSource{}, noCandidate
+	}
+	return model.ReviewerSource{}, errors.New(model.ErrNoCandidate)
 }
 
-func (s *Service) DeactivateTeam(teamName string) (map[string]interface{}, error) {
-	team, err := s.store.GetTeam(teamName)
+// DeactivateTeam deactivates every member of teamName and reassigns their
+// open reviews. Each reassignment is recorded in the PR history with reason
+// team_deactivation and actorID; PRs on which any deactivated reviewer is
+// left without a replacement are reported as failed_reassignments.
+func (s *Service) DeactivateTeam(ctx context.Context, teamName, actorID string) (map[string]interface{}, error) {
+	ctx, span := tracing.Start(ctx, "service.DeactivateTeam")
+	defer span.End()
+
+	team, err := s.store.GetTeam(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -225,7 +783,12 @@ func (s *Service) DeactivateTeam(teamName string) (map[string]interface{}, error
 		return nil, errors.New(model.ErrNotFound)
 	}
 
-	deactivatedUserIDs, err := s.store.DeactivateTeam(teamName)
+	deactivatedUserIDs, err := s.store.DeactivateTeam(ctx, teamName, func(userIDs []string) []model.Event {
+		return []model.Event{model.NewEvent(model.EventTeamDeactivated, map[string]interface{}{
+			"team_name":         teamName,
+			"deactivated_users": userIDs,
+		})}
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -239,80 +802,55 @@ func (s *Service) DeactivateTeam(teamName string) (map[string]interface{}, error
 		}, nil
 	}
 
-	prIDs, err := s.store.GetOpenPRsForReviewers(deactivatedUserIDs)
+	prIDs, err := s.store.GetOpenPRsForReviewers(ctx, deactivatedUserIDs)
 	if err != nil {
 		return nil, err
 	}
 
+	deactivated := make(map[string]bool, len(deactivatedUserIDs))
+	for _, userID := range deactivatedUserIDs {
+		deactivated[userID] = true
+	}
+
 	reassignedPRs := []string{}
 	failedPRs := []string{}
 
 	for _, prID := range prIDs {
-		pr, err := s.store.GetPR(prID)
+		pr, err := s.store.GetPR(ctx, prID)
 		if err != nil || pr == nil {
 			failedPRs = append(failedPRs, prID)
 			continue
 		}
 
-		hasDeactivated := false
+		touched, ok := false, true
 		for _, reviewerID := range pr.AssignedReviewers {
-			for _, deactivatedID := range deactivatedUserIDs {
-				if reviewerID == deactivatedID {
-					hasDeactivated = true
-					break
-				}
+			if !deactivated[reviewerID] {
+				continue
 			}
-			if hasDeactivated {
-				break
+			touched = true
+			newReviewer, err := s.replacementFor(ctx, pr, reviewerID)
+			if err == nil {
+				err = s.reassign(ctx, prID, reviewerID, newReviewer, model.ReasonTeamDeactivation, actorID)
 			}
-		}
-
-		if !hasDeactivated {
-			continue
-		}
-
-		for _, reviewerID := range pr.AssignedReviewers {
-			isDeactivated := false
-			for _, deactivatedID := range deactivatedUserIDs {
-				if reviewerID == deactivatedID {
-					isDeactivated = true
-					break
-				}
+			if err != nil {
+				slog.WarnContext(ctx, "Deactivated reviewer left in place",
+					"pull_request_id", prID, "reviewer_id", reviewerID, "err", err)
+				ok = false
+				continue
 			}
-
-			if isDeactivated {
-				oldUser, err := s.store.GetUser(reviewerID)
-				if err != nil || oldUser == nil {
-					continue
-				}
-
-				candidates, err := s.store.GetActiveTeamMembers(oldUser.TeamName, "")
-				if err != nil {
-					continue
-				}
-
-				excludeUsers := map[string]bool{pr.AuthorID: true}
-				for _, rid := range pr.AssignedReviewers {
-					excludeUsers[rid] = true
-				}
-
-				availableCandidates := []model.User{}
-				for _, c := range candidates {
-					if !excludeUsers[c.UserID] {
-						availableCandidates = append(availableCandidates, c)
-					}
-				}
-
-				if len(availableCandidates) > 0 {
-					newReviewer := availableCandidates[s.rng.Intn(len(availableCandidates))]
-					err = s.store.ReassignReviewer(prID, reviewerID, newReviewer.UserID)
-					if err == nil {
-						reassignedPRs = append(reassignedPRs, prID)
-						break
-					}
-				}
+			// Later replacements on the same PR must not pick newReviewer again.
+			if pr, err = s.store.GetPR(ctx, prID); err != nil {
+				return nil, err
 			}
 		}
+
+		switch {
+		case !touched:
+		case ok:
+			reassignedPRs = append(reassignedPRs, prID)
+		default:
+			failedPRs = append(failedPRs, prID)
+		}
 	}
 
 	return map[string]interface{}{
@@ -322,3 +860,55 @@ func (s *Service) DeactivateTeam(teamName string) (map[string]interface{}, error
 		"failed_reassignments": failedPRs,
 	}, nil
 }
+
+// reassign hands oldUserID's review of prID over to newReviewer, recording
+// reason and actorID in the history together with a reviewer.reassigned
+// event.
+func (s *Service) reassign(ctx context.Context, prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string) error {
+	event := reassignedEvent(prID, oldUserID, newReviewer, reason, actorID)
+	if err := s.store.ReassignReviewer(ctx, prID, oldUserID, newReviewer, reason, actorID, event); err != nil {
+		return err
+	}
+	reviewerReassigned(ctx, prID, oldUserID, newReviewer, reason)
+	return nil
+}
+
+// reviewerReassigned counts and logs a stored reassignment.
+func reviewerReassigned(ctx context.Context, prID, oldUserID string, newReviewer model.ReviewerSource, reason string) {
+	metrics.Assigned(model.AssignmentReassigned, reason, 1)
+	slog.InfoContext(ctx, "Reviewer reassigned",
+		"pull_request_id", prID, "old_reviewer_id", oldUserID, "new_reviewer_id", newReviewer.UserID,
+		"source", newReviewer.Source, "reason", reason)
+}
+
+// noCandidateFound records that no reviewer of teamName could be assigned.
+func noCandidateFound(ctx context.Context, teamName string) {
+	metrics.NoCandidate(teamName)
+	slog.WarnContext(ctx, "No reviewer candidate", "team_name", teamName)
+}
+
+func reassignedEvent(prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string) model.Event {
+	data := map[string]interface{}{
+		"pull_request_id": prID,
+		"old_reviewer_id": oldUserID,
+		"new_reviewer_id": newReviewer.UserID,
+		"source":          newReviewer.Source,
+		"source_name":     newReviewer.Name,
+		"reason":          reason,
+	}
+	if actorID != "" {
+		data["actor_id"] = actorID
+	}
+	return model.NewEvent(model.EventReviewerReassigned, data)
+}
+
+// GetPRHistory returns the append-only reviewer history of a PR.
+func (s *Service) GetPRHistory(ctx context.Context, prID string) ([]model.ReviewerAssignment, error) {
+	ctx, span := tracing.Start(ctx, "service.GetPRHistory")
+	defer span.End()
+
+	if _, err := s.getPR(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.store.ListReviewerAssignments(ctx, prID)
+}
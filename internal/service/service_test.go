@@ -0,0 +1,307 @@
+package service
+
+import (
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/storage"
+)
+
+func newTestService(t *testing.T, teams ...model.Team) *Service {
+	t.Helper()
+
+	svc := New(storage.NewMemory())
+	for _, team := range teams {
+		if _, err := svc.CreateTeam(t.Context(), team); err != nil {
+			t.Fatalf("Failed to create team %s: %v", team.TeamName, err)
+		}
+	}
+	return svc
+}
+
+func testTeam(name string, userIDs ...string) model.Team {
+	team := model.Team{TeamName: name}
+	for _, id := range userIDs {
+		team.Members = append(team.Members, model.TeamMember{UserID: id, Username: id, IsActive: true})
+	}
+	return team
+}
+
+func TestCreatePR(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+
+	t.Run("AssignsTwoReviewers", func(t *testing.T) {
+		pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+		if err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+		if len(pr.AssignedReviewers) != 2 {
+			t.Fatalf("Expected 2 reviewers, got %d", len(pr.AssignedReviewers))
+		}
+		for _, reviewerID := range pr.AssignedReviewers {
+			if reviewerID == "u1" {
+				t.Error("Author should not be assigned as reviewer")
+			}
+		}
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		_, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+		if err == nil || err.Error() != model.ErrPRExists {
+			t.Fatalf("Expected %s, got %v", model.ErrPRExists, err)
+		}
+	})
+
+	t.Run("UnknownAuthor", func(t *testing.T) {
+		_, err := svc.CreatePR(t.Context(), "pr-2", "Add feature", "nobody", CreatePROptions{})
+		if err == nil || err.Error() != model.ErrNotFound {
+			t.Fatalf("Expected %s, got %v", model.ErrNotFound, err)
+		}
+	})
+
+	t.Run("SkipsInactiveMembers", func(t *testing.T) {
+		if _, err := svc.SetUserActive(t.Context(), "u3", false); err != nil {
+			t.Fatalf("Failed to deactivate user: %v", err)
+		}
+		if _, err := svc.SetUserActive(t.Context(), "u4", false); err != nil {
+			t.Fatalf("Failed to deactivate user: %v", err)
+		}
+
+		pr, err := svc.CreatePR(t.Context(), "pr-3", "Fix bug", "u1", CreatePROptions{})
+		if err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+		if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u2" {
+			t.Errorf("Expected only u2 to be assigned, got %v", pr.AssignedReviewers)
+		}
+	})
+}
+
+func TestReassignReviewer(t *testing.T) {
+	svc := newTestService(t,
+		testTeam("backend", "u1", "u2", "u3", "u4"),
+		testTeam("small", "s1", "s2", "s3"),
+	)
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	oldReviewer := pr.AssignedReviewers[0]
+
+	pr, replacedBy, err := svc.ReassignReviewer(t.Context(), "pr-1", oldReviewer, "")
+	if err != nil {
+		t.Fatalf("Failed to reassign: %v", err)
+	}
+	if replacedBy == oldReviewer || replacedBy == "u1" {
+		t.Errorf("Unexpected replacement %s", replacedBy)
+	}
+	for _, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == oldReviewer {
+			t.Errorf("Old reviewer %s is still assigned", oldReviewer)
+		}
+	}
+
+	if _, _, err := svc.ReassignReviewer(t.Context(), "pr-1", "u1", ""); err == nil || err.Error() != model.ErrNotAssigned {
+		t.Errorf("Expected %s, got %v", model.ErrNotAssigned, err)
+	}
+
+	if _, err := svc.CreatePR(t.Context(), "pr-2", "Small change", "s1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if _, _, err := svc.ReassignReviewer(t.Context(), "pr-2", "s2", ""); err == nil || err.Error() != model.ErrNoCandidate {
+		t.Errorf("Expected %s, got %v", model.ErrNoCandidate, err)
+	}
+
+	if _, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{}); err != nil {
+		t.Fatalf("Failed to merge PR: %v", err)
+	}
+	if _, _, err := svc.ReassignReviewer(t.Context(), "pr-1", pr.AssignedReviewers[0], ""); err == nil || err.Error() != model.ErrPRMerged {
+		t.Errorf("Expected %s, got %v", model.ErrPRMerged, err)
+	}
+}
+
+func TestDeactivateTeam(t *testing.T) {
+	svc := newTestService(t,
+		testTeam("backend", "b1", "b2", "b3"),
+		testTeam("frontend", "f1", "f2", "f3", "f4"),
+	)
+
+	if _, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "b1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+
+	result, err := svc.DeactivateTeam(t.Context(), "frontend", "")
+	if err != nil {
+		t.Fatalf("Failed to deactivate team: %v", err)
+	}
+	if got := len(result["deactivated_users"].([]string)); got != 4 {
+		t.Errorf("Expected 4 deactivated users, got %d", got)
+	}
+
+	team, err := svc.GetTeam(t.Context(), "frontend")
+	if err != nil {
+		t.Fatalf("Failed to get team: %v", err)
+	}
+	for _, m := range team.Members {
+		if m.IsActive {
+			t.Errorf("User %s should be inactive", m.UserID)
+		}
+	}
+
+	if _, err := svc.DeactivateTeam(t.Context(), "missing", ""); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s, got %v", model.ErrNotFound, err)
+	}
+}
+
+func TestReviewerCount(t *testing.T) {
+	team := testTeam("backend", "u1", "u2", "u3", "u4", "u5")
+	team.ReviewersRequired = 1
+	team.MaxReviewers = 3
+	svc := newTestService(t, team)
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Default", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 1 {
+		t.Errorf("Expected team default of 1 reviewer, got %d", len(pr.AssignedReviewers))
+	}
+
+	pr, err = svc.CreatePR(t.Context(), "pr-2", "Override", "u1", CreatePROptions{ReviewersRequired: 3})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 3 {
+		t.Errorf("Expected override of 3 reviewers, got %d", len(pr.AssignedReviewers))
+	}
+
+	if _, err := svc.CreatePR(t.Context(), "pr-3", "Too many", "u1", CreatePROptions{ReviewersRequired: 4}); err == nil || err.Error() != model.ErrInvalidInput {
+		t.Errorf("Expected %s, got %v", model.ErrInvalidInput, err)
+	}
+
+	required := 4
+	updated, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{ReviewersRequired: &required})
+	if err != nil {
+		t.Fatalf("Failed to update team: %v", err)
+	}
+	if updated.ReviewersRequired != 4 || updated.MaxReviewers != 4 {
+		t.Errorf("Expected 4/4 reviewers, got %d/%d", updated.ReviewersRequired, updated.MaxReviewers)
+	}
+
+	maxReviewers := 2
+	if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{MaxReviewers: &maxReviewers}); err == nil || err.Error() != model.ErrInvalidInput {
+		t.Errorf("Expected %s, got %v", model.ErrInvalidInput, err)
+	}
+}
+
+func TestPRHistory(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	oldReviewer := pr.AssignedReviewers[0]
+	_, newReviewer, err := svc.ReassignReviewer(t.Context(), "pr-1", oldReviewer, "lead")
+	if err != nil {
+		t.Fatalf("Failed to reassign: %v", err)
+	}
+
+	history, err := svc.GetPRHistory(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(history) != 4 {
+		t.Fatalf("Expected 2 assignments and a reassignment pair, got %+v", history)
+	}
+	for _, a := range history[:2] {
+		if a.Action != model.AssignmentAssigned || a.Reason != model.ReasonAuto || a.ActorID != nil {
+			t.Errorf("Expected automatic assignment, got %+v", a)
+		}
+	}
+	unassigned, reassigned := history[2], history[3]
+	if unassigned.UserID != oldReviewer || unassigned.Action != model.AssignmentUnassigned {
+		t.Errorf("Expected %s unassigned, got %+v", oldReviewer, unassigned)
+	}
+	if reassigned.UserID != newReviewer || reassigned.Action != model.AssignmentReassigned ||
+		reassigned.Reason != model.ReasonManual || reassigned.ReplacedUserID == nil || *reassigned.ReplacedUserID != oldReviewer ||
+		reassigned.ActorID == nil || *reassigned.ActorID != "lead" {
+		t.Errorf("Unexpected reassignment entry %+v", reassigned)
+	}
+
+	if _, err := svc.GetPRHistory(t.Context(), "missing"); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s, got %v", model.ErrNotFound, err)
+	}
+}
+
+func TestDeactivateTeamReportsUnreplacedPRs(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "b1", "b2", "b3"))
+
+	if _, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "b1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+
+	// The whole team goes inactive, so nobody is left to take the review.
+	result, err := svc.DeactivateTeam(t.Context(), "backend", "admin")
+	if err != nil {
+		t.Fatalf("Failed to deactivate team: %v", err)
+	}
+	failed := result["failed_reassignments"].([]string)
+	if len(failed) != 1 || failed[0] != "pr-1" {
+		t.Errorf("Expected pr-1 in failed_reassignments, got %v", failed)
+	}
+	if reassigned := result["reassigned_prs"].([]string); len(reassigned) != 0 {
+		t.Errorf("Expected no reassigned PRs, got %v", reassigned)
+	}
+}
+
+func TestDeactivateTeamReplacesEveryReviewer(t *testing.T) {
+	svc := newTestService(t,
+		testTeam("backend", "b1", "b2", "b3", "b4", "b5"),
+		testTeam("platform", "p1", "p2"),
+	)
+	if _, err := svc.CreateReviewerPool(t.Context(), "platform", []string{"p1", "p2"}); err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	pools := []string{"platform"}
+	if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{FallbackPools: &pools}); err != nil {
+		t.Fatalf("Failed to set fallback pools: %v", err)
+	}
+	// Only b1 is active in backend, so both slots go to the pool.
+	for _, userID := range []string{"b2", "b3", "b4", "b5"} {
+		if _, err := svc.SetUserActive(t.Context(), userID, false); err != nil {
+			t.Fatalf("Failed to deactivate user: %v", err)
+		}
+	}
+	if _, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "b1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	for _, userID := range []string{"b2", "b3"} {
+		if _, err := svc.SetUserActive(t.Context(), userID, true); err != nil {
+			t.Fatalf("Failed to activate user: %v", err)
+		}
+	}
+
+	result, err := svc.DeactivateTeam(t.Context(), "platform", "admin")
+	if err != nil {
+		t.Fatalf("Failed to deactivate team: %v", err)
+	}
+	if reassigned := result["reassigned_prs"].([]string); len(reassigned) != 1 {
+		t.Fatalf("Expected pr-1 reassigned, got %v (failed %v)", reassigned, result["failed_reassignments"])
+	}
+	pr, _ := svc.store.GetPR(t.Context(), "pr-1")
+	if !isAssigned(pr, "b2") || !isAssigned(pr, "b3") {
+		t.Errorf("Expected both deactivated reviewers replaced by b2 and b3, got %v", pr.AssignedReviewers)
+	}
+	history, _ := svc.GetPRHistory(t.Context(), "pr-1")
+	reassignments := 0
+	for _, a := range history {
+		if a.Action == model.AssignmentReassigned && a.Reason == model.ReasonTeamDeactivation {
+			reassignments++
+		}
+	}
+	if reassignments != 2 {
+		t.Errorf("Expected 2 team_deactivation reassignments, got %+v", history)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// ProcessSLABreaches handles every reviewer assignment that has been waiting
+// for a verdict longer than its team's review_sla_minutes. Teams with
+// sla_action reassign get the review handed to a replacement with reason sla;
+// the rest, and reassignments that find no candidate, escalate with a
+// review.sla_breached event addressed to the team lead. Each assignment is
+// handled once, and the returned breaches are the ones handled by this call.
+func (s *Service) ProcessSLABreaches(ctx context.Context) ([]model.SLABreach, error) {
+	ctx, span := tracing.Start(ctx, "service.ProcessSLABreaches")
+	defer span.End()
+
+	overdue, err := s.store.ListOverdueAssignments(ctx, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	settingsByTeam := map[string]*model.TeamSettings{}
+	breaches := []model.SLABreach{}
+	for _, a := range overdue {
+		settings, ok := settingsByTeam[a.TeamName]
+		if !ok {
+			if settings, err = s.store.GetTeamSettings(ctx, a.TeamName); err != nil {
+				return nil, err
+			}
+			settingsByTeam[a.TeamName] = settings
+		}
+		if settings == nil {
+			continue
+		}
+
+		breach, err := s.handleSLABreach(ctx, a, settings)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Handled concurrently, or the reviewer is gone already.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		breaches = append(breaches, *breach)
+	}
+	return breaches, nil
+}
+
+func (s *Service) handleSLABreach(ctx context.Context, a model.OverdueAssignment, settings *model.TeamSettings) (*model.SLABreach, error) {
+	if settings.SLAAction == model.SLAReassign {
+		pr, err := s.getPR(ctx, a.PullRequestID)
+		if err != nil {
+			return nil, err
+		}
+		// Without a replacement the breach is escalated instead.
+		if newReviewer, err := s.replacementFor(ctx, pr, a.ReviewerID); err == nil {
+			// The breach is recorded in the same transaction as the swap, so
+			// that a review is never moved without a trace in sla_breaches.
+			breach, err := s.store.RecordSLABreach(ctx, a, model.SLAActionReassigned, &newReviewer, "",
+				reassignedEvent(a.PullRequestID, a.ReviewerID, newReviewer, model.ReasonSLA, ""),
+				slaBreachedEvent(a, model.SLAActionReassigned, newReviewer.UserID, ""))
+			if err != nil {
+				return nil, err
+			}
+			reviewerReassigned(ctx, a.PullRequestID, a.ReviewerID, newReviewer, model.ReasonSLA)
+			return breach, nil
+		}
+	}
+
+	return s.store.RecordSLABreach(ctx, a, model.SLAActionEscalated, nil, settings.TeamLeadID,
+		slaBreachedEvent(a, model.SLAActionEscalated, "", settings.TeamLeadID))
+}
+
+// ListSLABreaches returns up to limit handled breaches, newest first,
+// optionally limited to teamName.
+func (s *Service) ListSLABreaches(ctx context.Context, teamName string, limit int) ([]model.SLABreach, error) {
+	ctx, span := tracing.Start(ctx, "service.ListSLABreaches")
+	defer span.End()
+
+	if teamName != "" {
+		exists, err := s.store.TeamExists(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, model.NewError(model.ErrNotFound, "team not found")
+		}
+	}
+	return s.store.ListSLABreaches(ctx, teamName, limit)
+}
+
+// checkTeamLead verifies that teamLeadID is an existing user or one of
+// members, which are about to be created with the team. An empty ID means
+// the team has no lead.
+func (s *Service) checkTeamLead(ctx context.Context, teamLeadID string, members []model.TeamMember) error {
+	if teamLeadID == "" {
+		return nil
+	}
+	for _, m := range members {
+		if m.UserID == teamLeadID {
+			return nil
+		}
+	}
+	user, err := s.store.GetUser(ctx, teamLeadID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return model.NewError(model.ErrInvalidInput, fmt.Sprintf("unknown team_lead_id %s", teamLeadID))
+	}
+	return nil
+}
+
+func slaBreachedEvent(a model.OverdueAssignment, action, newReviewerID, teamLeadID string) model.Event {
+	data := map[string]interface{}{
+		"pull_request_id": a.PullRequestID,
+		"reviewer_id":     a.ReviewerID,
+		"team_name":       a.TeamName,
+		"assigned_at":     model.FormatTime(a.AssignedAt),
+		"sla_minutes":     a.SLAMinutes,
+		"action":          action,
+	}
+	if newReviewerID != "" {
+		data["new_reviewer_id"] = newReviewerID
+	}
+	if teamLeadID != "" {
+		data["team_lead_id"] = teamLeadID
+	}
+	return model.NewEvent(model.EventSLABreached, data)
+}
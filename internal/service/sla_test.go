@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/storage"
+)
+
+func TestSLAEscalation(t *testing.T) {
+	team := testTeam("backend", "u1", "u2", "u3", "lead")
+	team.ReviewSLAMinutes = 60
+	team.TeamLeadID = "lead"
+	svc := newTestService(t, team)
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	reviewed, waiting := pr.AssignedReviewers[0], pr.AssignedReviewers[1]
+	if _, _, err := svc.SubmitReview(t.Context(), "pr-1", reviewed, model.VerdictApproved, nil); err != nil {
+		t.Fatalf("Failed to submit review: %v", err)
+	}
+
+	svc.now = func() time.Time { return time.Now().Add(30 * time.Minute) }
+	if breaches, _ := svc.ProcessSLABreaches(t.Context()); len(breaches) != 0 {
+		t.Fatalf("Expected no breaches within the SLA, got %+v", breaches)
+	}
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	breaches, err := svc.ProcessSLABreaches(t.Context())
+	if err != nil {
+		t.Fatalf("SLA check failed: %v", err)
+	}
+	if len(breaches) != 1 || breaches[0].ReviewerID != waiting {
+		t.Fatalf("Expected one breach for %s, got %+v", waiting, breaches)
+	}
+	b := breaches[0]
+	if b.Action != model.SLAActionEscalated || b.TeamLeadID == nil || *b.TeamLeadID != "lead" {
+		t.Errorf("Expected escalation to lead, got %+v", b)
+	}
+
+	// A breach is handled once.
+	if again, _ := svc.ProcessSLABreaches(t.Context()); len(again) != 0 {
+		t.Errorf("Expected breach to be handled once, got %+v", again)
+	}
+	listed, err := svc.ListSLABreaches(t.Context(), "backend", 10)
+	if err != nil || len(listed) != 1 {
+		t.Errorf("Expected one listed breach, got %+v (%v)", listed, err)
+	}
+	if _, err := svc.ListSLABreaches(t.Context(), "nope", 10); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s for unknown team, got %v", model.ErrNotFound, err)
+	}
+}
+
+func TestSLAReassign(t *testing.T) {
+	reassign := model.SLAReassign
+	sla := 60
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"), testTeam("small", "s1", "s2", "s3"))
+	for _, name := range []string{"backend", "small"} {
+		update := model.TeamSettingsUpdate{ReviewSLAMinutes: &sla, SLAAction: &reassign}
+		if _, err := svc.UpdateTeam(t.Context(), name, update); err != nil {
+			t.Fatalf("Failed to update team %s: %v", name, err)
+		}
+	}
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	slow := pr.AssignedReviewers[0]
+	if _, _, err := svc.SubmitReview(t.Context(), "pr-1", pr.AssignedReviewers[1], model.VerdictApproved, nil); err != nil {
+		t.Fatalf("Failed to submit review: %v", err)
+	}
+	if _, err := svc.CreatePR(t.Context(), "pr-2", "Small fix", "s1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	breaches, err := svc.ProcessSLABreaches(t.Context())
+	if err != nil {
+		t.Fatalf("SLA check failed: %v", err)
+	}
+
+	actions := map[string]string{}
+	for _, b := range breaches {
+		actions[b.PullRequestID+"/"+b.ReviewerID] = b.Action
+	}
+	if actions["pr-1/"+slow] != model.SLAActionReassigned || len(breaches) != 3 {
+		t.Fatalf("Expected %s reassigned and both pr-2 reviewers escalated, got %+v", slow, breaches)
+	}
+	// Nobody in the small team is left to take over.
+	for key, action := range actions {
+		if key != "pr-1/"+slow && action != model.SLAActionEscalated {
+			t.Errorf("Expected %s escalated, got %s", key, action)
+		}
+	}
+
+	pr, _ = svc.store.GetPR(t.Context(), "pr-1")
+	if isAssigned(pr, slow) {
+		t.Errorf("Expected %s to be replaced, got %v", slow, pr.AssignedReviewers)
+	}
+	history, _ := svc.GetPRHistory(t.Context(), "pr-1")
+	if last := history[len(history)-1]; last.Reason != model.ReasonSLA {
+		t.Errorf("Expected reassignment with reason sla, got %+v", last)
+	}
+
+	// The replacement starts a fresh SLA.
+	svc.now = func() time.Time { return time.Now().Add(30 * time.Minute) }
+	if again, _ := svc.ProcessSLABreaches(t.Context()); len(again) != 0 {
+		t.Errorf("Expected no new breaches, got %+v", again)
+	}
+}
+
+// failingSLAStore fails every RecordSLABreach call with err.
+type failingSLAStore struct {
+	storage.Repository
+	err error
+}
+
+func (s *failingSLAStore) RecordSLABreach(ctx context.Context, a model.OverdueAssignment, action string, newReviewer *model.ReviewerSource, teamLeadID string, events ...model.Event) (*model.SLABreach, error) {
+	return nil, s.err
+}
+
+func TestSLAReassignIsAtomic(t *testing.T) {
+	reassign := model.SLAReassign
+	sla := 60
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+	if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{ReviewSLAMinutes: &sla, SLAAction: &reassign}); err != nil {
+		t.Fatalf("Failed to update team: %v", err)
+	}
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{ReviewersRequired: 2})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	// Recording the breach fails, e.g. because the context is cancelled
+	// during shutdown: the review must stay where it was.
+	store := svc.store
+	svc.store = &failingSLAStore{Repository: store, err: context.Canceled}
+	if _, err := svc.ProcessSLABreaches(t.Context()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+	svc.store = store
+
+	after, _ := svc.store.GetPR(t.Context(), "pr-1")
+	for _, reviewerID := range pr.AssignedReviewers {
+		if !isAssigned(after, reviewerID) {
+			t.Errorf("Expected %s to stay assigned, got %v", reviewerID, after.AssignedReviewers)
+		}
+	}
+	history, _ := svc.GetPRHistory(t.Context(), "pr-1")
+	if len(history) != 2 {
+		t.Errorf("Expected no reassignment in history, got %+v", history)
+	}
+
+	// The next run still sees the overdue assignments and handles them.
+	breaches, err := svc.ProcessSLABreaches(t.Context())
+	if err != nil {
+		t.Fatalf("SLA check failed: %v", err)
+	}
+	if len(breaches) != 2 {
+		t.Fatalf("Expected 2 breaches, got %+v", breaches)
+	}
+	recorded, _ := svc.ListSLABreaches(t.Context(), "backend", 10)
+	if len(recorded) != 2 {
+		t.Errorf("Expected 2 recorded breaches, got %+v", recorded)
+	}
+	for _, b := range breaches {
+		if b.Action != model.SLAActionReassigned || b.NewReviewerID == nil {
+			t.Errorf("Expected a reassignment, got %+v", b)
+		}
+	}
+}
+
+// staleOverdueStore lists the overdue assignments captured in overdue, as
+// a check that ran before a concurrent change would see them.
+type staleOverdueStore struct {
+	storage.Repository
+	overdue []model.OverdueAssignment
+}
+
+func (s *staleOverdueStore) ListOverdueAssignments(ctx context.Context, now time.Time) ([]model.OverdueAssignment, error) {
+	return s.overdue, nil
+}
+
+func TestSLAEscalationSkipsStaleAssignments(t *testing.T) {
+	team := testTeam("backend", "u1", "u2", "u3", "u4", "lead")
+	team.ReviewSLAMinutes = 60
+	team.TeamLeadID = "lead"
+	svc := newTestService(t, team)
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		if _, err := svc.CreatePR(t.Context(), id, "Add feature", "u1", CreatePROptions{}); err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+	}
+	later := time.Now().Add(2 * time.Hour)
+	overdue, err := svc.store.ListOverdueAssignments(t.Context(), later)
+	if err != nil || len(overdue) != 4 {
+		t.Fatalf("Expected 4 overdue assignments, got %+v (%v)", overdue, err)
+	}
+
+	// After the listing, one reviewer of pr-1 is replaced and pr-2 merges.
+	pr, _ := svc.store.GetPR(t.Context(), "pr-1")
+	if _, _, err := svc.ReassignReviewer(t.Context(), "pr-1", pr.AssignedReviewers[0], ""); err != nil {
+		t.Fatalf("Failed to reassign: %v", err)
+	}
+	if _, err := svc.MergePR(t.Context(), "pr-2", MergeOptions{}); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+
+	store := svc.store
+	svc.store = &staleOverdueStore{Repository: store, overdue: overdue}
+	svc.now = func() time.Time { return later }
+	breaches, err := svc.ProcessSLABreaches(t.Context())
+	svc.store = store
+	if err != nil {
+		t.Fatalf("SLA check failed: %v", err)
+	}
+	if len(breaches) != 1 || breaches[0].PullRequestID != "pr-1" || breaches[0].ReviewerID != pr.AssignedReviewers[1] {
+		t.Errorf("Expected only the remaining pr-1 reviewer escalated, got %+v", breaches)
+	}
+	if listed, _ := svc.ListSLABreaches(t.Context(), "", 10); len(listed) != 1 {
+		t.Errorf("Expected one recorded breach, got %+v", listed)
+	}
+}
+
+func TestSLASettingsValidation(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2"))
+
+	bad := "ignore"
+	negative := -1
+	unknown := "nobody"
+	cases := []struct {
+		name   string
+		update model.TeamSettingsUpdate
+	}{
+		{"Action", model.TeamSettingsUpdate{SLAAction: &bad}},
+		{"NegativeSLA", model.TeamSettingsUpdate{ReviewSLAMinutes: &negative}},
+		{"UnknownLead", model.TeamSettingsUpdate{TeamLeadID: &unknown}},
+	}
+	for _, tc := range cases {
+		if _, err := svc.UpdateTeam(t.Context(), "backend", tc.update); err == nil || err.Error() != model.ErrInvalidInput {
+			t.Errorf("%s: expected %s, got %v", tc.name, model.ErrInvalidInput, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// StaffingWarnings returns the warnings to show for an open PR that has
+// fewer reviewers than it requires, if its author's team asked to be
+// warned. Teams with understaffed_policy allow get none; reject never lets
+// such a PR be created in the first place.
+func (s *Service) StaffingWarnings(ctx context.Context, pr *model.PullRequest) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "service.StaffingWarnings")
+	defer span.End()
+
+	warnings := []string{}
+	if pr.Status != model.StatusOpen || len(pr.AssignedReviewers) >= pr.ReviewersRequired {
+		return warnings, nil
+	}
+
+	author, err := s.store.GetUser(ctx, pr.AuthorID)
+	if err != nil || author == nil {
+		return warnings, err
+	}
+	settings, err := s.store.GetTeamSettings(ctx, author.TeamName)
+	if err != nil || settings == nil {
+		return warnings, err
+	}
+	if settings.UnderstaffedPolicy == model.UnderstaffedWarn {
+		warnings = append(warnings, understaffedMessage(len(pr.AssignedReviewers), pr.ReviewersRequired))
+	}
+	return warnings, nil
+}
+
+// ListUnderReviewedPRs returns open PRs with fewer active reviewers than
+// they require, optionally limited to PRs authored in teamName.
+func (s *Service) ListUnderReviewedPRs(ctx context.Context, teamName string) ([]model.UnderReviewedPR, error) {
+	ctx, span := tracing.Start(ctx, "service.ListUnderReviewedPRs")
+	defer span.End()
+
+	if teamName != "" {
+		exists, err := s.store.TeamExists(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, model.NewError(model.ErrNotFound, "team not found")
+		}
+	}
+	return s.store.ListUnderReviewedPRs(ctx, teamName)
+}
+
+func understaffedMessage(assigned, required int) string {
+	return fmt.Sprintf("only %d of %d required reviewers could be assigned", assigned, required)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestUnderstaffedPolicy(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2"), testTeam("solo", "s1"))
+
+	// warn is the default: the PR is created and a warning is reported.
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if pr.ReviewersRequired != 2 || len(pr.AssignedReviewers) != 1 {
+		t.Fatalf("Expected 1 of 2 reviewers, got %d of %d", len(pr.AssignedReviewers), pr.ReviewersRequired)
+	}
+	warnings, err := svc.StaffingWarnings(t.Context(), pr)
+	if err != nil || len(warnings) != 1 {
+		t.Errorf("Expected one warning, got %v (%v)", warnings, err)
+	}
+
+	allow := model.UnderstaffedAllow
+	if _, err := svc.UpdateTeam(t.Context(), "backend", model.TeamSettingsUpdate{UnderstaffedPolicy: &allow}); err != nil {
+		t.Fatalf("Failed to update team: %v", err)
+	}
+	if warnings, _ := svc.StaffingWarnings(t.Context(), pr); len(warnings) != 0 {
+		t.Errorf("Expected no warnings under allow, got %v", warnings)
+	}
+
+	reject := model.UnderstaffedReject
+	if _, err := svc.UpdateTeam(t.Context(), "solo", model.TeamSettingsUpdate{UnderstaffedPolicy: &reject}); err != nil {
+		t.Fatalf("Failed to update team: %v", err)
+	}
+	if _, err := svc.CreatePR(t.Context(), "pr-2", "Alone", "s1", CreatePROptions{}); err == nil || err.Error() != model.ErrUnderstaffed {
+		t.Errorf("Expected %s, got %v", model.ErrUnderstaffed, err)
+	}
+	if _, err := svc.CreatePR(t.Context(), "pr-2", "Alone", "s1", CreatePROptions{Draft: true}); err != nil {
+		t.Fatalf("Expected a draft to be accepted, got %v", err)
+	}
+	if _, err := svc.MarkReady(t.Context(), "pr-2", 0); err == nil || err.Error() != model.ErrUnderstaffed {
+		t.Errorf("Expected %s marking ready, got %v", model.ErrUnderstaffed, err)
+	}
+
+	bad := "ignore"
+	if _, err := svc.UpdateTeam(t.Context(), "solo", model.TeamSettingsUpdate{UnderstaffedPolicy: &bad}); err == nil || err.Error() != model.ErrInvalidInput {
+		t.Errorf("Expected %s, got %v", model.ErrInvalidInput, err)
+	}
+}
+
+func TestListUnderReviewedPRs(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3"), testTeam("frontend", "f1", "f2"))
+
+	if _, err := svc.CreatePR(t.Context(), "pr-1", "Staffed", "u1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if _, err := svc.CreatePR(t.Context(), "pr-2", "Short", "f1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+
+	prs, err := svc.ListUnderReviewedPRs(t.Context(), "")
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(prs) != 1 || prs[0].PullRequestID != "pr-2" || prs[0].Missing != 1 || prs[0].TeamName != "frontend" {
+		t.Fatalf("Expected pr-2 missing one reviewer, got %+v", prs)
+	}
+
+	// A deactivated reviewer no longer counts.
+	pr, _ := svc.store.GetPR(t.Context(), "pr-1")
+	svc.SetUserActive(t.Context(), pr.AssignedReviewers[0], false)
+	prs, _ = svc.ListUnderReviewedPRs(t.Context(), "backend")
+	if len(prs) != 1 || prs[0].PullRequestID != "pr-1" || len(prs[0].ActiveReviewers) != 1 {
+		t.Errorf("Expected pr-1 with one active reviewer, got %+v", prs)
+	}
+
+	if _, err := svc.ListUnderReviewedPRs(t.Context(), "nobody"); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s, got %v", model.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+type StatisticsOptions struct {
+	// TeamName limits the statistics to PRs authored in the team and to its
+	// members' review load. Empty means every team.
+	TeamName string
+	// From and To bound the window as RFC 3339 timestamps or YYYY-MM-DD
+	// dates in UTC. From is inclusive; To is exclusive, except that a date
+	// includes the whole day. Either may be empty.
+	From string
+	To   string
+	// Limit caps top_reviewers and open_review_load. Zero means
+	// model.DefaultStatisticsLimit.
+	Limit int
+}
+
+func (s *Service) GetStatistics(ctx context.Context, opts StatisticsOptions) (*model.Statistics, error) {
+	ctx, span := tracing.Start(ctx, "service.GetStatistics")
+	defer span.End()
+
+	filter := model.StatisticsFilter{TeamName: opts.TeamName, Limit: opts.Limit}
+	if filter.Limit == 0 {
+		filter.Limit = model.DefaultStatisticsLimit
+	}
+	if filter.Limit < 0 {
+		return nil, model.NewError(model.ErrInvalidInput, "limit must be a positive integer")
+	}
+
+	var err error
+	if filter.From, filter.To, err = parseWindow(opts.From, opts.To); err != nil {
+		return nil, err
+	}
+
+	if opts.TeamName != "" {
+		exists, err := s.store.TeamExists(ctx, opts.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, model.NewError(model.ErrNotFound, "team not found")
+		}
+	}
+
+	stats, err := s.store.GetStatistics(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	stats.TeamName = opts.TeamName
+	stats.From, stats.To = formatWindow(filter.From, filter.To)
+	return stats, nil
+}
+
+// parseWindow parses the from and to query parameters shared by the
+// statistics endpoints.
+func parseWindow(from, to string) (*time.Time, *time.Time, error) {
+	start, err := parseWindowBound(from, false)
+	if err != nil {
+		return nil, nil, model.NewError(model.ErrInvalidInput, "from must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	end, err := parseWindowBound(to, true)
+	if err != nil {
+		return nil, nil, model.NewError(model.ErrInvalidInput, "to must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	if start != nil && end != nil && !start.Before(*end) {
+		return nil, nil, model.NewError(model.ErrInvalidInput, "from must be before to")
+	}
+	return start, end, nil
+}
+
+func formatWindow(from, to *time.Time) (*string, *string) {
+	var start, end *string
+	if from != nil {
+		formatted := model.FormatTime(*from)
+		start = &formatted
+	}
+	if to != nil {
+		formatted := model.FormatTime(*to)
+		end = &formatted
+	}
+	return start, end
+}
+
+// parseWindowBound parses one end of a statistics window. A date used as
+// the upper bound means the end of that day.
+func parseWindowBound(value string, upper bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if day, err := time.Parse(model.DateLayout, value); err == nil {
+		if upper {
+			day = day.AddDate(0, 0, 1)
+		}
+		return &day, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// TeamLoad returns the current open PRs and open reviews of every team.
+func (s *Service) TeamLoad(ctx context.Context) ([]model.TeamLoad, error) {
+	ctx, span := tracing.Start(ctx, "service.TeamLoad")
+	defer span.End()
+
+	return s.store.GetTeamLoad(ctx)
+}
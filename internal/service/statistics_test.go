@@ -0,0 +1,128 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestStatistics(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"), testTeam("frontend", "f1", "f2", "f3"))
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		if _, err := svc.CreatePR(t.Context(), id, "Backend change", "u1", CreatePROptions{}); err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+	}
+	if _, err := svc.CreatePR(t.Context(), "pr-3", "Frontend change", "f1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if _, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{}); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	pr, _ := svc.store.GetPR(t.Context(), "pr-2")
+	if _, _, err := svc.ReassignReviewer(t.Context(), "pr-2", pr.AssignedReviewers[0], ""); err != nil {
+		t.Fatalf("Failed to reassign: %v", err)
+	}
+
+	stats, err := svc.GetStatistics(t.Context(), StatisticsOptions{TeamName: "backend", Limit: 2})
+	if err != nil {
+		t.Fatalf("Failed to get statistics: %v", err)
+	}
+	if stats.TotalPRs != 2 || stats.OpenPRs != 1 || stats.MergedPRs != 1 {
+		t.Errorf("Expected 2 backend PRs, 1 open and 1 merged, got %+v", stats)
+	}
+	if stats.TimeToMerge.Count != 1 || stats.TimeToMerge.MedianSeconds == nil || stats.TimeToMerge.P90Seconds == nil {
+		t.Errorf("Expected time to merge of one PR, got %+v", stats.TimeToMerge)
+	}
+	if stats.Reassignments.Total != 1 || stats.Reassignments.ByReason[model.ReasonManual] != 1 {
+		t.Errorf("Expected one manual reassignment, got %+v", stats.Reassignments)
+	}
+	if len(stats.TopReviewers) != 2 || len(stats.OpenReviewLoad) != 2 {
+		t.Errorf("Expected lists capped at 2, got %+v and %+v", stats.TopReviewers, stats.OpenReviewLoad)
+	}
+	for _, l := range stats.OpenReviewLoad {
+		if l.TeamName != "backend" {
+			t.Errorf("Expected only backend members in the load, got %+v", l)
+		}
+	}
+	if top := stats.OpenReviewLoad[0]; top.OpenReviews != 1 {
+		t.Errorf("Expected the busiest member to review pr-2 only, got %+v", top)
+	}
+
+	all, _ := svc.GetStatistics(t.Context(), StatisticsOptions{})
+	if all.TotalPRs != 3 || all.TimeToMerge.Count != 1 {
+		t.Errorf("Expected 3 PRs overall, got %+v", all)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(model.DateLayout)
+	future, err := svc.GetStatistics(t.Context(), StatisticsOptions{From: tomorrow})
+	if err != nil {
+		t.Fatalf("Failed to get statistics: %v", err)
+	}
+	if future.TotalPRs != 0 || future.TimeToMerge.Count != 0 || future.TimeToMerge.MedianSeconds != nil ||
+		len(future.TopReviewers) != 0 || future.Reassignments.Total != 0 {
+		t.Errorf("Expected an empty window, got %+v", future)
+	}
+	if future.From == nil || *future.From != tomorrow+"T00:00:00Z" {
+		t.Errorf("Expected the window to be echoed, got %v", future.From)
+	}
+
+	cases := []struct {
+		name string
+		opts StatisticsOptions
+		want string
+	}{
+		{"BadFrom", StatisticsOptions{From: "yesterday"}, model.ErrInvalidInput},
+		{"EmptyWindow", StatisticsOptions{From: "2026-07-10", To: "2026-07-09"}, model.ErrInvalidInput},
+		{"UnknownTeam", StatisticsOptions{TeamName: "nope"}, model.ErrNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := svc.GetStatistics(t.Context(), tc.opts); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestStatisticsWithNonUTCClock(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3"))
+	zone := time.FixedZone("UTC-10", -10*60*60)
+	svc.now = func() time.Time { return time.Now().In(zone) }
+
+	if _, err := svc.CreatePR(t.Context(), "pr-1", "Change", "u1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if _, err := svc.MergePR(t.Context(), "pr-1", MergeOptions{}); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+
+	// The bounds carry the clock's -10:00 offset; the PR is inside the
+	// first window and before the second however the zones line up.
+	hourAgo := svc.now().Add(-time.Hour).Format(time.RFC3339)
+	hourAhead := svc.now().Add(time.Hour).Format(time.RFC3339)
+	stats, err := svc.GetStatistics(t.Context(), StatisticsOptions{From: hourAgo, To: hourAhead})
+	if err != nil {
+		t.Fatalf("Failed to get statistics: %v", err)
+	}
+	if stats.TotalPRs != 1 || stats.TimeToMerge.Count != 1 || len(stats.TopReviewers) != 2 {
+		t.Errorf("Expected the PR inside the window, got %+v", stats)
+	}
+	if stats.From == nil || !strings.HasSuffix(*stats.From, "Z") {
+		t.Errorf("Expected the window echoed in UTC, got %v", stats.From)
+	}
+
+	later, err := svc.GetStatistics(t.Context(), StatisticsOptions{From: hourAhead})
+	if err != nil {
+		t.Fatalf("Failed to get statistics: %v", err)
+	}
+	if later.TotalPRs != 0 || later.TimeToMerge.Count != 0 || len(later.TopReviewers) != 0 {
+		t.Errorf("Expected an empty window, got %+v", later)
+	}
+
+	pr, _ := svc.store.GetPR(t.Context(), "pr-1")
+	if pr.CreatedAt == nil || !strings.HasSuffix(*pr.CreatedAt, "Z") {
+		t.Errorf("Expected timestamps stored in UTC, got %v", pr.CreatedAt)
+	}
+}
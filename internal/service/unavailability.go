@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// CreateUnavailability schedules an out-of-office window for userID.
+// startDate and endDate are inclusive and formatted as model.DateLayout.
+func (s *Service) CreateUnavailability(ctx context.Context, userID, startDate, endDate, reason string) (*model.Unavailability, error) {
+	ctx, span := tracing.Start(ctx, "service.CreateUnavailability")
+	defer span.End()
+
+	start, err := time.Parse(model.DateLayout, startDate)
+	if err != nil {
+		return nil, model.NewError(model.ErrInvalidInput, "start_date must be a YYYY-MM-DD date")
+	}
+	end, err := time.Parse(model.DateLayout, endDate)
+	if err != nil {
+		return nil, model.NewError(model.ErrInvalidInput, "end_date must be a YYYY-MM-DD date")
+	}
+	if end.Before(start) {
+		return nil, model.NewError(model.ErrInvalidInput, "end_date must not be before start_date")
+	}
+
+	user, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(model.ErrNotFound)
+	}
+
+	return s.store.CreateUnavailability(ctx, model.Unavailability{
+		UserID:    userID,
+		StartDate: model.FormatDate(start),
+		EndDate:   model.FormatDate(end),
+		Reason:    reason,
+	})
+}
+
+func (s *Service) ListUnavailability(ctx context.Context, userID string) ([]model.Unavailability, error) {
+	ctx, span := tracing.Start(ctx, "service.ListUnavailability")
+	defer span.End()
+
+	user, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(model.ErrNotFound)
+	}
+	return s.store.ListUnavailability(ctx, userID)
+}
+
+func (s *Service) DeleteUnavailability(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "service.DeleteUnavailability")
+	defer span.End()
+
+	err := s.store.DeleteUnavailability(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New(model.ErrNotFound)
+	}
+	return err
+}
+
+// ReassignUnavailableReviewers hands the open reviews of users whose
+// out-of-office window has started over to available replacements,
+// recording reason ooo. Each window is handled once, on the first run on or
+// after its start date, so windows that started while the job was not
+// running are caught up and a review without a replacement is not retried
+// for the rest of the leave. Any other failure leaves the user's windows
+// pending for the next run and is returned once all PRs were tried. It
+// returns the PRs that were touched and the ones for which no replacement
+// was found.
+func (s *Service) ReassignUnavailableReviewers(ctx context.Context) (reassigned, failed []string, err error) {
+	ctx, span := tracing.Start(ctx, "service.ReassignUnavailableReviewers")
+	defer span.End()
+
+	reassigned, failed = []string{}, []string{}
+
+	windows, err := s.store.ListUnreassignedUnavailability(ctx, s.now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(windows) == 0 {
+		return reassigned, failed, nil
+	}
+
+	away := make(map[string]bool, len(windows))
+	awayUserIDs := []string{}
+	for _, w := range windows {
+		if !away[w.UserID] {
+			away[w.UserID] = true
+			awayUserIDs = append(awayUserIDs, w.UserID)
+		}
+	}
+	prIDs, err := s.store.GetOpenPRsForReviewers(ctx, awayUserIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// retry holds the users whose hand-over failed for a reason other than
+	// a missing candidate, so that their windows are tried again.
+	retry := map[string]bool{}
+	var errs []error
+	for _, prID := range prIDs {
+		pr, err := s.store.GetPR(ctx, prID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pr == nil {
+			continue
+		}
+
+		ok, pending := true, false
+		for _, reviewerID := range pr.AssignedReviewers {
+			if !away[reviewerID] {
+				continue
+			}
+			newReviewer, err := s.replacementFor(ctx, pr, reviewerID)
+			if err == nil {
+				err = s.reassign(ctx, prID, reviewerID, newReviewer, model.ReasonOOO, "")
+			}
+			if err != nil && err.Error() == model.ErrNoCandidate {
+				ok = false
+				continue
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "OOO reassignment failed",
+					"pull_request_id", prID, "reviewer_id", reviewerID, "err", err)
+				retry[reviewerID] = true
+				pending = true
+				errs = append(errs, fmt.Errorf("reassign %s on %s: %w", reviewerID, prID, err))
+				continue
+			}
+			// Later replacements on the same PR must not pick newReviewer again.
+			if pr, err = s.store.GetPR(ctx, prID); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		switch {
+		case pending:
+		case ok:
+			reassigned = append(reassigned, prID)
+		default:
+			failed = append(failed, prID)
+		}
+	}
+
+	for _, w := range windows {
+		if retry[w.UserID] {
+			continue
+		}
+		if err := s.store.MarkUnavailabilityReassigned(ctx, w.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return reassigned, failed, errors.Join(errs...)
+}
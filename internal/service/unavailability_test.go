@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/storage"
+)
+
+func TestUnavailableUsersAreNotSelected(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+	svc.now = func() time.Time { return time.Date(2026, 7, 10, 12, 0, 0, 0, time.UTC) }
+
+	if _, err := svc.CreateUnavailability(t.Context(), "u2", "2026-07-10", "2026-07-20", "vacation"); err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+	// A window that has already ended does not matter.
+	if _, err := svc.CreateUnavailability(t.Context(), "u3", "2026-07-01", "2026-07-09", ""); err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	for _, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == "u2" {
+			t.Errorf("Out-of-office user was assigned: %v", pr.AssignedReviewers)
+		}
+	}
+
+	cases := []struct {
+		name, userID, start, end, want string
+	}{
+		{"BadDate", "u2", "10.07.2026", "2026-07-20", model.ErrInvalidInput},
+		{"EndBeforeStart", "u2", "2026-07-20", "2026-07-10", model.ErrInvalidInput},
+		{"UnknownUser", "nobody", "2026-07-10", "2026-07-20", model.ErrNotFound},
+	}
+	for _, tc := range cases {
+		_, err := svc.CreateUnavailability(t.Context(), tc.userID, tc.start, tc.end, "")
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestReassignUnavailableReviewers(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+	today := time.Date(2026, 7, 10, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return today.AddDate(0, 0, -1) }
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	away := pr.AssignedReviewers[0]
+	window, err := svc.CreateUnavailability(t.Context(), away, "2026-07-10", "2026-07-12", "")
+	if err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+
+	// The leave has not started yet.
+	if reassigned, _, _ := svc.ReassignUnavailableReviewers(t.Context()); len(reassigned) != 0 {
+		t.Fatalf("Expected nothing reassigned before the leave, got %v", reassigned)
+	}
+
+	svc.now = func() time.Time { return today }
+	reassigned, failed, err := svc.ReassignUnavailableReviewers(t.Context())
+	if err != nil {
+		t.Fatalf("Reassign job failed: %v", err)
+	}
+	if len(reassigned) != 1 || len(failed) != 0 {
+		t.Fatalf("Expected pr-1 reassigned, got %v (failed %v)", reassigned, failed)
+	}
+
+	pr, _ = svc.store.GetPR(t.Context(), "pr-1")
+	if isAssigned(pr, away) {
+		t.Errorf("Expected %s to be replaced, got %v", away, pr.AssignedReviewers)
+	}
+	history, _ := svc.GetPRHistory(t.Context(), "pr-1")
+	if last := history[len(history)-1]; last.Reason != model.ReasonOOO {
+		t.Errorf("Expected reason %s, got %+v", model.ReasonOOO, last)
+	}
+
+	if err := svc.DeleteUnavailability(t.Context(), window.ID); err != nil {
+		t.Fatalf("Failed to delete window: %v", err)
+	}
+	if err := svc.DeleteUnavailability(t.Context(), window.ID); err == nil || err.Error() != model.ErrNotFound {
+		t.Errorf("Expected %s, got %v", model.ErrNotFound, err)
+	}
+}
+
+func TestReassignUnavailableReviewersHandlesEachWindowOnce(t *testing.T) {
+	svc := newTestService(t, testTeam("pair", "u1", "u2"))
+	today := time.Date(2026, 7, 10, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return today }
+
+	if _, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{}); err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	if _, err := svc.CreateUnavailability(t.Context(), "u2", "2026-07-08", "2026-07-12", ""); err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+
+	// The leave started two days ago and nobody can take over.
+	_, failed, err := svc.ReassignUnavailableReviewers(t.Context())
+	if err != nil {
+		t.Fatalf("Reassign job failed: %v", err)
+	}
+	if len(failed) != 1 || failed[0] != "pr-1" {
+		t.Fatalf("Expected pr-1 without replacement, got %v", failed)
+	}
+
+	svc.now = func() time.Time { return today.AddDate(0, 0, 1) }
+	reassigned, failed, err := svc.ReassignUnavailableReviewers(t.Context())
+	if err != nil {
+		t.Fatalf("Reassign job failed: %v", err)
+	}
+	if len(reassigned)+len(failed) != 0 {
+		t.Errorf("Expected the handled window to be skipped, got %v (failed %v)", reassigned, failed)
+	}
+
+	windows, _ := svc.ListUnavailability(t.Context(), "u2")
+	if len(windows) != 1 || windows[0].ReviewsReassignedAt == nil {
+		t.Errorf("Expected the window to be marked as handled, got %+v", windows)
+	}
+}
+
+// failingReassignStore fails every ReassignReviewer call with err.
+type failingReassignStore struct {
+	storage.Repository
+	err error
+}
+
+func (s *failingReassignStore) ReassignReviewer(ctx context.Context, prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string, events ...model.Event) error {
+	return s.err
+}
+
+func TestReassignUnavailableReviewersRetriesStorageErrors(t *testing.T) {
+	svc := newTestService(t, testTeam("backend", "u1", "u2", "u3", "u4"))
+	svc.now = func() time.Time { return time.Date(2026, 7, 10, 9, 0, 0, 0, time.UTC) }
+
+	pr, err := svc.CreatePR(t.Context(), "pr-1", "Add feature", "u1", CreatePROptions{})
+	if err != nil {
+		t.Fatalf("Failed to create PR: %v", err)
+	}
+	away := pr.AssignedReviewers[0]
+	if _, err := svc.CreateUnavailability(t.Context(), away, "2026-07-10", "2026-07-12", ""); err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+
+	dbErr := errors.New("connection reset by peer")
+	store := svc.store
+	svc.store = &failingReassignStore{Repository: store, err: dbErr}
+	reassigned, failed, err := svc.ReassignUnavailableReviewers(t.Context())
+	svc.store = store
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("Expected %v, got %v", dbErr, err)
+	}
+	if len(reassigned)+len(failed) != 0 {
+		t.Errorf("Expected pr-1 to be neither reassigned nor failed, got %v (failed %v)", reassigned, failed)
+	}
+	windows, _ := svc.ListUnavailability(t.Context(), away)
+	if len(windows) != 1 || windows[0].ReviewsReassignedAt != nil {
+		t.Fatalf("Expected the window to stay pending, got %+v", windows)
+	}
+
+	reassigned, _, err = svc.ReassignUnavailableReviewers(t.Context())
+	if err != nil {
+		t.Fatalf("Reassign job failed: %v", err)
+	}
+	if len(reassigned) != 1 || reassigned[0] != "pr-1" {
+		t.Errorf("Expected pr-1 reassigned on the next run, got %v", reassigned)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+func (s *Service) CreateWebhookSubscription(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "service.CreateWebhookSubscription")
+	defer span.End()
+
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, model.NewError(model.ErrInvalidInput, "url must be an absolute http(s) URL")
+	}
+	for _, eventType := range sub.EventTypes {
+		if !isKnownEventType(eventType) {
+			return nil, model.NewError(model.ErrInvalidInput, fmt.Sprintf("unknown event type %q", eventType))
+		}
+	}
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+
+	if sub.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		sub.Secret = hex.EncodeToString(secret)
+	}
+	sub.IsActive = true
+
+	return s.store.CreateWebhookSubscription(ctx, sub)
+}
+
+// ListWebhookSubscriptions returns all subscriptions with their secrets
+// redacted; the secret is only revealed once, on creation.
+func (s *Service) ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "service.ListWebhookSubscriptions")
+	defer span.End()
+
+	subs, err := s.store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *Service) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "service.DeleteWebhookSubscription")
+	defer span.End()
+
+	if err := s.store.DeleteWebhookSubscription(ctx, id); err != nil {
+		return errors.New(model.ErrNotFound)
+	}
+	return nil
+}
+
+func (s *Service) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]model.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "service.ListWebhookDeliveries")
+	defer span.End()
+
+	switch status {
+	case "", model.DeliveryPending, model.DeliveryDelivered, model.DeliveryDead:
+	default:
+		return nil, model.NewError(model.ErrInvalidInput, fmt.Sprintf("unknown delivery status %q", status))
+	}
+	return s.store.ListWebhookDeliveries(ctx, subscriptionID, status, limit)
+}
+
+func (s *Service) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]model.WebhookDeliveryAttempt, error) {
+	ctx, span := tracing.Start(ctx, "service.ListWebhookDeliveryAttempts")
+	defer span.End()
+
+	return s.store.ListWebhookDeliveryAttempts(ctx, deliveryID)
+}
+
+func (s *Service) ListWebhookDeadLetters(ctx context.Context, limit int) ([]model.WebhookDeadLetter, error) {
+	ctx, span := tracing.Start(ctx, "service.ListWebhookDeadLetters")
+	defer span.End()
+
+	return s.store.ListWebhookDeadLetters(ctx, limit)
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, t := range model.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+// Package sla periodically handles reviewer assignments that have been
+// waiting for a verdict longer than their team's review SLA.
+package sla
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"pr-reviewer-service/internal/health"
+	"pr-reviewer-service/internal/service"
+)
+
+type Job struct {
+	service   *service.Service
+	interval  time.Duration
+	heartbeat *health.Heartbeat
+}
+
+// NewJob returns a job that runs every interval. Running it on several
+// nodes is safe: each breach is recorded once, in the same transaction as
+// the reassignment it triggers, and only the node that records it
+// escalates or reassigns.
+func NewJob(svc *service.Service, interval time.Duration) *Job {
+	return &Job{service: svc, interval: interval}
+}
+
+// SetHeartbeat makes the job report the outcome of every run to hb.
+func (j *Job) SetHeartbeat(hb *health.Heartbeat) {
+	j.heartbeat = hb
+}
+
+// Run checks for breaches once immediately and then every interval until
+// ctx is cancelled.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		breaches, err := j.service.ProcessSLABreaches(ctx)
+		j.heartbeat.Beat(err)
+		if err != nil {
+			slog.ErrorContext(ctx, "SLA job failed", "err", err)
+		}
+		for _, b := range breaches {
+			slog.InfoContext(ctx, "Review SLA breached",
+				"reviewer_id", b.ReviewerID, "pull_request_id", b.PullRequestID, "sla_minutes", b.SLAMinutes, "action", b.Action)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
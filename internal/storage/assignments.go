@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+// insertAssignment appends an entry to the reviewer history as part of tx.
+// An empty actorID or replacedUserID is stored as NULL.
+func insertAssignment(ctx context.Context, tx *sql.Tx, prID, userID, action, reason, actorID, replacedUserID string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO reviewer_assignments (pull_request_id, user_id, action, reason, actor_id, replaced_user_id, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)`,
+		prID, userID, action, reason, actorID, replacedUserID, time.Now().UTC())
+	return err
+}
+
+// ListReviewerAssignments returns the reviewer history of a PR, oldest first.
+func (s *Storage) ListReviewerAssignments(ctx context.Context, prID string) ([]model.ReviewerAssignment, error) {
+	ctx, done := observe(ctx, "ListReviewerAssignments")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, pull_request_id, user_id, action, reason, actor_id, replaced_user_id, created_at
+		FROM reviewer_assignments
+		WHERE pull_request_id = $1
+		ORDER BY id`, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.ReviewerAssignment{}
+	for rows.Next() {
+		var a model.ReviewerAssignment
+		var actorID, replacedUserID sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&a.ID, &a.PullRequestID, &a.UserID, &a.Action, &a.Reason, &actorID, &replacedUserID, &createdAt); err != nil {
+			return nil, err
+		}
+		if actorID.Valid {
+			a.ActorID = &actorID.String
+		}
+		if replacedUserID.Valid {
+			a.ReplacedUserID = &replacedUserID.String
+		}
+		a.CreatedAt = model.FormatTime(createdAt)
+		history = append(history, a)
+	}
+	return history, rows.Err()
+}
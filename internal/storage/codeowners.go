@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+// SetTeamCodeOwners replaces the CODEOWNERS content of teamName.
+func (s *Storage) SetTeamCodeOwners(ctx context.Context, teamName, content string) (*model.CodeOwners, error) {
+	ctx, done := observe(ctx, "SetTeamCodeOwners")
+	defer done()
+
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO team_codeowners (team_name, content, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_name) DO UPDATE
+		SET content = EXCLUDED.content, updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`, teamName, content, time.Now().UTC()).Scan(&updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CodeOwners{
+		TeamName:  teamName,
+		Content:   content,
+		UpdatedAt: model.FormatTime(updatedAt),
+	}, nil
+}
+
+// GetTeamCodeOwners returns the CODEOWNERS content of teamName, or nil if
+// the team has never uploaded any.
+func (s *Storage) GetTeamCodeOwners(ctx context.Context, teamName string) (*model.CodeOwners, error) {
+	ctx, done := observe(ctx, "GetTeamCodeOwners")
+	defer done()
+
+	owners := model.CodeOwners{TeamName: teamName}
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx, `
+		SELECT content, updated_at FROM team_codeowners WHERE team_name = $1`, teamName).
+		Scan(&owners.Content, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	owners.UpdatedAt = model.FormatTime(updatedAt)
+	return &owners, nil
+}
+
+func (s *Storage) getChangedFiles(ctx context.Context, prID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT path FROM pr_changed_files
+		WHERE pull_request_id = $1
+		ORDER BY path`, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []string{}
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+	return files, rows.Err()
+}
@@ -0,0 +1,730 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+// MemoryStorage is a thread-safe in-memory Repository. It mirrors the
+// semantics of the PostgreSQL Storage and is intended for unit tests and
+// local demos that should not depend on a database.
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	teams map[string]*memTeam
+	users map[string]*memUser
+	prs   map[string]*memPR
+	// accounts maps provider -> login -> user_id.
+	accounts map[string]map[string]string
+	// codeOwners maps team_name -> CODEOWNERS content.
+	codeOwners map[string]model.CodeOwners
+	pools      map[string]*model.ReviewerPool
+
+	subscriptions []model.WebhookSubscription
+	deliveries    []*memDelivery
+	attempts      []model.WebhookDeliveryAttempt
+	deadLetters   []model.WebhookDeadLetter
+	outbox        []*memOutboxEntry
+	overrides     []model.MergeOverride
+	// unavailability holds out-of-office windows in creation order.
+	unavailability []model.Unavailability
+	slaBreaches    []model.SLABreach
+	lastID         int64
+}
+
+type memTeam struct {
+	settings  model.TeamSettings
+	createdAt time.Time
+}
+
+type memUser struct {
+	user      model.User
+	updatedAt time.Time
+}
+
+type memPR struct {
+	pr        model.PullRequest
+	createdAt time.Time
+	mergedAt  *time.Time
+	closedAt  *time.Time
+	reviewers []string
+	// sources maps each current reviewer to where they were picked from.
+	sources map[string]model.ReviewerSource
+	// assignedAt maps each current reviewer to when they were assigned.
+	assignedAt map[string]time.Time
+	reviews    []model.Review
+	history    []model.ReviewerAssignment
+}
+
+func NewMemory() *MemoryStorage {
+	return &MemoryStorage{
+		teams:      make(map[string]*memTeam),
+		users:      make(map[string]*memUser),
+		prs:        make(map[string]*memPR),
+		accounts:   make(map[string]map[string]string),
+		codeOwners: make(map[string]model.CodeOwners),
+		pools:      make(map[string]*model.ReviewerPool),
+	}
+}
+
+func (m *MemoryStorage) Close() error {
+	return nil
+}
+
+func (m *MemoryStorage) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *MemoryStorage) CreateTeam(ctx context.Context, teamName string, settings model.TeamSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.teams[teamName]; ok {
+		return fmt.Errorf("team %q already exists", teamName)
+	}
+	settings.FallbackPools = append([]string{}, settings.FallbackPools...)
+	m.teams[teamName] = &memTeam{settings: settings, createdAt: time.Now()}
+	return nil
+}
+
+func (m *MemoryStorage) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.teams[teamName]
+	return ok, nil
+}
+
+func (m *MemoryStorage) GetTeam(ctx context.Context, teamName string) (*model.Team, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.teams[teamName]
+	if !ok {
+		return nil, nil
+	}
+
+	members := []model.TeamMember{}
+	for _, u := range m.sortedUsers() {
+		if u.user.TeamName != teamName {
+			continue
+		}
+		members = append(members, model.TeamMember{
+			UserID:   u.user.UserID,
+			Username: u.user.Username,
+			IsActive: u.user.IsActive,
+		})
+	}
+
+	settings := t.settings
+	settings.FallbackPools = append([]string{}, settings.FallbackPools...)
+	return &model.Team{
+		TeamName:     teamName,
+		TeamSettings: settings,
+		Members:      members,
+	}, nil
+}
+
+func (m *MemoryStorage) GetTeamSettings(ctx context.Context, teamName string) (*model.TeamSettings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.teams[teamName]
+	if !ok {
+		return nil, nil
+	}
+	settings := t.settings
+	settings.FallbackPools = append([]string{}, settings.FallbackPools...)
+	return &settings, nil
+}
+
+func (m *MemoryStorage) UpdateTeamSettings(ctx context.Context, teamName string, settings model.TeamSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.teams[teamName]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	t.settings = settings
+	t.settings.FallbackPools = append([]string{}, settings.FallbackPools...)
+	return nil
+}
+
+func (m *MemoryStorage) UpsertUser(ctx context.Context, user model.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.teams[user.TeamName]; !ok {
+		return fmt.Errorf("team %q does not exist", user.TeamName)
+	}
+	if existing, ok := m.users[user.UserID]; ok {
+		// Like the ON CONFLICT clause in Storage, an upsert does not touch
+		// the personal review limit.
+		user.MaxOpenReviews = existing.user.MaxOpenReviews
+	}
+	m.users[user.UserID] = &memUser{user: user, updatedAt: time.Now()}
+	return nil
+}
+
+func (m *MemoryStorage) GetUser(ctx context.Context, userID string) (*model.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	user := u.user
+	return &user, nil
+}
+
+func (m *MemoryStorage) LinkExternalAccount(ctx context.Context, account model.ExternalAccount) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[account.UserID]; !ok {
+		return fmt.Errorf("user %q does not exist", account.UserID)
+	}
+	if m.accounts[account.Provider] == nil {
+		m.accounts[account.Provider] = make(map[string]string)
+	}
+	m.accounts[account.Provider][account.Login] = account.UserID
+	return nil
+}
+
+func (m *MemoryStorage) GetUserByExternalLogin(ctx context.Context, provider, login string) (*model.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	userID, ok := m.accounts[provider][login]
+	if !ok {
+		return nil, nil
+	}
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	user := u.user
+	return &user, nil
+}
+
+func (m *MemoryStorage) SetUserActive(ctx context.Context, userID string, isActive bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.user.IsActive = isActive
+	u.updatedAt = time.Now()
+	return nil
+}
+
+func (m *MemoryStorage) SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if limit != nil {
+		v := *limit
+		limit = &v
+	}
+	u.user.MaxOpenReviews = limit
+	u.updatedAt = time.Now()
+	return nil
+}
+
+func (m *MemoryStorage) GetActiveTeamMembers(ctx context.Context, teamName, excludeUserID string, at time.Time) ([]model.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	away := m.unavailableOn(model.FormatDate(at))
+	users := []model.User{}
+	for _, u := range m.sortedUsers() {
+		if u.user.TeamName == teamName && u.user.IsActive && u.user.UserID != excludeUserID && !away[u.user.UserID] {
+			users = append(users, u.user)
+		}
+	}
+	return users, nil
+}
+
+func (m *MemoryStorage) CreatePR(ctx context.Context, pr model.PullRequest, events ...model.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.prs[pr.PullRequestID]; ok {
+		return fmt.Errorf("pull request %q already exists", pr.PullRequestID)
+	}
+	if _, ok := m.users[pr.AuthorID]; !ok {
+		return fmt.Errorf("author %q does not exist", pr.AuthorID)
+	}
+
+	seen := make(map[string]bool, len(pr.ReviewerSources))
+	for _, r := range pr.ReviewerSources {
+		if _, ok := m.users[r.UserID]; !ok {
+			return fmt.Errorf("reviewer %q does not exist", r.UserID)
+		}
+		if seen[r.UserID] {
+			return fmt.Errorf("reviewer %q assigned twice", r.UserID)
+		}
+		seen[r.UserID] = true
+	}
+
+	p := &memPR{
+		pr: model.PullRequest{
+			PullRequestID:     pr.PullRequestID,
+			PullRequestName:   pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			Status:            pr.Status,
+			ChangedFiles:      append([]string{}, pr.ChangedFiles...),
+			ReviewersRequired: pr.ReviewersRequired,
+		},
+		createdAt:  time.Now(),
+		sources:    make(map[string]model.ReviewerSource),
+		assignedAt: make(map[string]time.Time),
+	}
+	m.addReviewers(p, pr.ReviewerSources)
+	m.prs[pr.PullRequestID] = p
+	return m.appendOutbox(ctx, events)
+}
+
+func (m *MemoryStorage) PRExists(ctx context.Context, prID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.prs[prID]
+	return ok, nil
+}
+
+func (m *MemoryStorage) GetPR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.prs[prID]
+	if !ok {
+		return nil, nil
+	}
+
+	pr := p.pr
+	createdAt := model.FormatTime(p.createdAt)
+	pr.CreatedAt = &createdAt
+	if p.mergedAt != nil {
+		mergedAt := model.FormatTime(*p.mergedAt)
+		pr.MergedAt = &mergedAt
+	}
+	if p.closedAt != nil {
+		closedAt := model.FormatTime(*p.closedAt)
+		pr.ClosedAt = &closedAt
+	}
+	pr.AssignedReviewers = append([]string{}, p.reviewers...)
+	pr.ReviewerSources = make([]model.ReviewerSource, 0, len(p.reviewers))
+	for _, reviewerID := range p.reviewers {
+		pr.ReviewerSources = append(pr.ReviewerSources, p.sources[reviewerID])
+	}
+	pr.Reviews = latestVerdicts(p)
+	pr.ChangedFiles = append([]string{}, p.pr.ChangedFiles...)
+	return &pr, nil
+}
+
+// latestVerdicts mirrors Storage.getLatestVerdicts: the newest review of each
+// currently assigned reviewer, ordered by user_id.
+func latestVerdicts(p *memPR) []model.ReviewVerdict {
+	assigned := make(map[string]bool, len(p.reviewers))
+	for _, reviewerID := range p.reviewers {
+		assigned[reviewerID] = true
+	}
+
+	latest := make(map[string]model.Review)
+	for _, r := range p.reviews {
+		if assigned[r.UserID] {
+			latest[r.UserID] = r
+		}
+	}
+
+	verdicts := []model.ReviewVerdict{}
+	for _, r := range latest {
+		verdicts = append(verdicts, model.ReviewVerdict{
+			UserID:      r.UserID,
+			Verdict:     r.Verdict,
+			SubmittedAt: r.SubmittedAt,
+		})
+	}
+	sort.Slice(verdicts, func(i, j int) bool {
+		return verdicts[i].UserID < verdicts[j].UserID
+	})
+	return verdicts
+}
+
+func (m *MemoryStorage) AddReview(ctx context.Context, review model.Review, events ...model.Event) (*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.prs[review.PullRequestID]
+	if !ok || p.pr.Status != model.StatusOpen {
+		return nil, sql.ErrNoRows
+	}
+	assigned := false
+	for _, reviewerID := range p.reviewers {
+		if reviewerID == review.UserID {
+			assigned = true
+			break
+		}
+	}
+	if !assigned {
+		return nil, sql.ErrNoRows
+	}
+
+	m.lastID++
+	review.ID = m.lastID
+	review.SubmittedAt = model.FormatTime(time.Now())
+	p.reviews = append(p.reviews, review)
+
+	if err := m.appendOutbox(ctx, events); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (m *MemoryStorage) MergePR(ctx context.Context, prID string, override *model.MergeOverride, events ...model.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.prs[prID]
+	if !ok || p.pr.Status != model.StatusOpen {
+		return sql.ErrNoRows
+	}
+	mergedAt := time.Now()
+	p.pr.Status = model.StatusMerged
+	p.mergedAt = &mergedAt
+
+	if override != nil {
+		m.lastID++
+		o := *override
+		o.ID = m.lastID
+		o.PullRequestID = prID
+		o.CreatedAt = model.FormatTime(mergedAt)
+		m.overrides = append(m.overrides, o)
+	}
+	return m.appendOutbox(ctx, events)
+}
+
+func (m *MemoryStorage) ListMergeOverrides(ctx context.Context, limit int) ([]model.MergeOverride, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	overrides := []model.MergeOverride{}
+	for i := len(m.overrides) - 1; i >= 0 && len(overrides) < limit; i-- {
+		overrides = append(overrides, m.overrides[i])
+	}
+	return overrides, nil
+}
+
+func (m *MemoryStorage) ReassignReviewer(ctx context.Context, prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string, events ...model.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.reassignReviewer(prID, oldUserID, newReviewer, reason, actorID); err != nil {
+		return err
+	}
+	return m.appendOutbox(ctx, events)
+}
+
+// reassignReviewer performs the swap of ReassignReviewer. Callers must hold
+// m.mu for writing.
+func (m *MemoryStorage) reassignReviewer(prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string) error {
+	newUserID := newReviewer.UserID
+	p, ok := m.prs[prID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	idx := -1
+	for i, reviewerID := range p.reviewers {
+		if reviewerID == oldUserID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return sql.ErrNoRows
+	}
+	if _, ok := m.users[newUserID]; !ok {
+		return fmt.Errorf("reviewer %q does not exist", newUserID)
+	}
+	for _, reviewerID := range p.reviewers {
+		if reviewerID == newUserID {
+			return fmt.Errorf("reviewer %q already assigned", newUserID)
+		}
+	}
+
+	reviewers := append([]string{}, p.reviewers[:idx]...)
+	reviewers = append(reviewers, p.reviewers[idx+1:]...)
+	p.reviewers = append(reviewers, newUserID)
+	delete(p.sources, oldUserID)
+	delete(p.assignedAt, oldUserID)
+	p.sources[newUserID] = newReviewer
+	p.assignedAt[newUserID] = time.Now()
+	m.recordAssignment(p, oldUserID, model.AssignmentUnassigned, reason, actorID, "")
+	m.recordAssignment(p, newUserID, model.AssignmentReassigned, reason, actorID, oldUserID)
+	return nil
+}
+
+// addReviewers assigns reviewers to the PR and records each assignment in
+// its history. Callers must hold m.mu for writing.
+func (m *MemoryStorage) addReviewers(p *memPR, reviewers []model.ReviewerSource) {
+	for _, r := range reviewers {
+		p.reviewers = append(p.reviewers, r.UserID)
+		p.sources[r.UserID] = r
+		p.assignedAt[r.UserID] = time.Now()
+		m.recordAssignment(p, r.UserID, model.AssignmentAssigned, model.ReasonAuto, "", "")
+	}
+}
+
+// recordAssignment appends to the PR's reviewer history. Callers must hold
+// m.mu for writing.
+func (m *MemoryStorage) recordAssignment(p *memPR, userID, action, reason, actorID, replacedUserID string) {
+	m.lastID++
+	a := model.ReviewerAssignment{
+		ID:            m.lastID,
+		PullRequestID: p.pr.PullRequestID,
+		UserID:        userID,
+		Action:        action,
+		Reason:        reason,
+		CreatedAt:     model.FormatTime(time.Now()),
+	}
+	if actorID != "" {
+		a.ActorID = &actorID
+	}
+	if replacedUserID != "" {
+		a.ReplacedUserID = &replacedUserID
+	}
+	p.history = append(p.history, a)
+}
+
+func (m *MemoryStorage) GetLastAssignedReviewer(ctx context.Context, userIDs []string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		wanted[userID] = true
+	}
+	var last model.ReviewerAssignment
+	for _, p := range m.prs {
+		for _, a := range p.history {
+			if a.ID > last.ID && wanted[a.UserID] && a.Action != model.AssignmentUnassigned {
+				last = a
+			}
+		}
+	}
+	return last.UserID, nil
+}
+
+func (m *MemoryStorage) ListReviewerAssignments(ctx context.Context, prID string) ([]model.ReviewerAssignment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := []model.ReviewerAssignment{}
+	if p, ok := m.prs[prID]; ok {
+		history = append(history, p.history...)
+	}
+	return history, nil
+}
+
+func (m *MemoryStorage) TransitionPR(ctx context.Context, prID, from, to string, reviewersRequired int, reviewers []model.ReviewerSource, events ...model.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.prs[prID]
+	if !ok || p.pr.Status != from {
+		return sql.ErrNoRows
+	}
+	for _, r := range reviewers {
+		if _, ok := p.sources[r.UserID]; ok {
+			return fmt.Errorf("reviewer %q already assigned", r.UserID)
+		}
+	}
+
+	p.pr.Status = to
+	if reviewersRequired > 0 {
+		p.pr.ReviewersRequired = reviewersRequired
+	}
+	p.closedAt = nil
+	if to == model.StatusClosed {
+		now := time.Now()
+		p.closedAt = &now
+	}
+	m.addReviewers(p, reviewers)
+	return m.appendOutbox(ctx, events)
+}
+
+func (m *MemoryStorage) GetPRsByReviewer(ctx context.Context, userID string) ([]model.PullRequestShort, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matched := []*memPR{}
+	for _, p := range m.prs {
+		if p.pr.Status == model.StatusClosed {
+			continue
+		}
+		for _, reviewerID := range p.reviewers {
+			if reviewerID == userID {
+				matched = append(matched, p)
+				break
+			}
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].createdAt.After(matched[j].createdAt)
+	})
+
+	prs := []model.PullRequestShort{}
+	for _, p := range matched {
+		prs = append(prs, model.PullRequestShort{
+			PullRequestID:   p.pr.PullRequestID,
+			PullRequestName: p.pr.PullRequestName,
+			AuthorID:        p.pr.AuthorID,
+			Status:          p.pr.Status,
+		})
+	}
+	return prs, nil
+}
+
+func (m *MemoryStorage) DeactivateTeam(ctx context.Context, teamName string, eventsFor func(deactivatedUserIDs []string) []model.Event) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	userIDs := []string{}
+	now := time.Now()
+	for _, u := range m.sortedUsers() {
+		if u.user.TeamName == teamName && u.user.IsActive {
+			u.user.IsActive = false
+			u.updatedAt = now
+			userIDs = append(userIDs, u.user.UserID)
+		}
+	}
+	if len(userIDs) > 0 && eventsFor != nil {
+		if err := m.appendOutbox(ctx, eventsFor(userIDs)); err != nil {
+			return nil, err
+		}
+	}
+	return userIDs, nil
+}
+
+func (m *MemoryStorage) GetOpenPRsForReviewers(ctx context.Context, userIDs []string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		wanted[userID] = true
+	}
+
+	prIDs := []string{}
+	for prID, p := range m.prs {
+		if p.pr.Status != model.StatusOpen {
+			continue
+		}
+		for _, reviewerID := range p.reviewers {
+			if wanted[reviewerID] {
+				prIDs = append(prIDs, prID)
+				break
+			}
+		}
+	}
+	sort.Strings(prIDs)
+	return prIDs, nil
+}
+
+func (m *MemoryStorage) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int, len(userIDs))
+	wanted := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		wanted[userID] = true
+	}
+	for _, p := range m.prs {
+		if p.pr.Status != model.StatusOpen {
+			continue
+		}
+		for _, reviewerID := range p.reviewers {
+			if wanted[reviewerID] {
+				counts[reviewerID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// ListUnderReviewedPRs returns the open PRs whose active reviewers are fewer
+// than their reviewers_required, oldest first. An empty teamName lists PRs
+// of every team; otherwise only PRs authored by members of teamName.
+func (m *MemoryStorage) ListUnderReviewedPRs(ctx context.Context, teamName string) ([]model.UnderReviewedPR, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	open := []*memPR{}
+	for _, p := range m.prs {
+		if p.pr.Status == model.StatusOpen {
+			open = append(open, p)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		if !open[i].createdAt.Equal(open[j].createdAt) {
+			return open[i].createdAt.Before(open[j].createdAt)
+		}
+		return open[i].pr.PullRequestID < open[j].pr.PullRequestID
+	})
+
+	prs := []model.UnderReviewedPR{}
+	for _, p := range open {
+		author := m.users[p.pr.AuthorID].user
+		if teamName != "" && author.TeamName != teamName {
+			continue
+		}
+		active := []string{}
+		for _, reviewerID := range p.reviewers {
+			if u, ok := m.users[reviewerID]; ok && u.user.IsActive {
+				active = append(active, reviewerID)
+			}
+		}
+		if len(active) >= p.pr.ReviewersRequired {
+			continue
+		}
+		sort.Strings(active)
+		prs = append(prs, model.UnderReviewedPR{
+			PullRequestID:     p.pr.PullRequestID,
+			PullRequestName:   p.pr.PullRequestName,
+			AuthorID:          p.pr.AuthorID,
+			TeamName:          author.TeamName,
+			ReviewersRequired: p.pr.ReviewersRequired,
+			ActiveReviewers:   active,
+			Missing:           p.pr.ReviewersRequired - len(active),
+		})
+	}
+	return prs, nil
+}
+
+// sortedUsers returns users ordered by user_id. Callers must hold m.mu.
+func (m *MemoryStorage) sortedUsers() []*memUser {
+	users := make([]*memUser, 0, len(m.users))
+	for _, u := range m.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].user.UserID < users[j].user.UserID
+	})
+	return users
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (m *MemoryStorage) SetTeamCodeOwners(ctx context.Context, teamName, content string) (*model.CodeOwners, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.teams[teamName]; !ok {
+		return nil, fmt.Errorf("team %q does not exist", teamName)
+	}
+	owners := model.CodeOwners{
+		TeamName:  teamName,
+		Content:   content,
+		UpdatedAt: model.FormatTime(time.Now()),
+	}
+	m.codeOwners[teamName] = owners
+	return &owners, nil
+}
+
+func (m *MemoryStorage) GetTeamCodeOwners(ctx context.Context, teamName string) (*model.CodeOwners, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	owners, ok := m.codeOwners[teamName]
+	if !ok {
+		return nil, nil
+	}
+	return &owners, nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+type memOutboxEntry struct {
+	id            int64
+	payload       []byte
+	attempts      int
+	nextAttemptAt time.Time
+	lastError     string
+	publishedAt   *time.Time
+	traceParent   string
+}
+
+// appendOutbox stores events the same way the PostgreSQL outbox does, as
+// serialized JSON. Callers must hold m.mu.
+func (m *MemoryStorage) appendOutbox(ctx context.Context, events []model.Event) error {
+	now := time.Now()
+	traceParent := tracing.TraceParent(ctx)
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		m.lastID++
+		m.outbox = append(m.outbox, &memOutboxEntry{
+			id:            m.lastID,
+			payload:       payload,
+			nextAttemptAt: now,
+			traceParent:   traceParent,
+		})
+	}
+	return nil
+}
+
+func (m *MemoryStorage) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := []model.OutboxEntry{}
+	for _, e := range m.outbox {
+		if len(entries) >= limit {
+			break
+		}
+		if e.publishedAt != nil || e.nextAttemptAt.After(now) {
+			continue
+		}
+		entry := model.OutboxEntry{ID: e.id, Attempts: e.attempts, TraceParent: e.traceParent}
+		if err := json.Unmarshal(e.payload, &entry.Event); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+		e.nextAttemptAt = now.Add(lease)
+	}
+	return entries, nil
+}
+
+func (m *MemoryStorage) MarkOutboxPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.outbox {
+		if e.id == id {
+			e.attempts++
+			e.lastError = ""
+			e.publishedAt = &publishedAt
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) MarkOutboxFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.outbox {
+		if e.id == id {
+			e.attempts++
+			e.lastError = errMsg
+			e.nextAttemptAt = nextAttemptAt
+			return nil
+		}
+	}
+	return nil
+}
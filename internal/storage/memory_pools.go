@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (m *MemoryStorage) CreateReviewerPool(ctx context.Context, pool model.ReviewerPool) (*model.ReviewerPool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.pools[pool.PoolName]; ok {
+		return nil, fmt.Errorf("reviewer pool %q already exists", pool.PoolName)
+	}
+	members, err := m.poolMembers(pool.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	pool.Members = members
+	pool.CreatedAt = model.FormatTime(time.Now())
+	stored := pool
+	m.pools[pool.PoolName] = &stored
+	return copyPool(&stored), nil
+}
+
+func (m *MemoryStorage) SetReviewerPoolMembers(ctx context.Context, poolName string, userIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool, ok := m.pools[poolName]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	members, err := m.poolMembers(userIDs)
+	if err != nil {
+		return err
+	}
+	pool.Members = members
+	return nil
+}
+
+// poolMembers mirrors the reviewer_pool_members table: members must exist,
+// duplicates collapse and they are kept in user_id order. Callers must hold
+// m.mu.
+func (m *MemoryStorage) poolMembers(userIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(userIDs))
+	members := []string{}
+	for _, userID := range userIDs {
+		if _, ok := m.users[userID]; !ok {
+			return nil, fmt.Errorf("user %q does not exist", userID)
+		}
+		if !seen[userID] {
+			seen[userID] = true
+			members = append(members, userID)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+func copyPool(pool *model.ReviewerPool) *model.ReviewerPool {
+	c := *pool
+	c.Members = append([]string{}, pool.Members...)
+	return &c
+}
+
+func (m *MemoryStorage) GetReviewerPool(ctx context.Context, poolName string) (*model.ReviewerPool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pool, ok := m.pools[poolName]
+	if !ok {
+		return nil, nil
+	}
+	return copyPool(pool), nil
+}
+
+func (m *MemoryStorage) ListReviewerPools(ctx context.Context) ([]model.ReviewerPool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pools := []model.ReviewerPool{}
+	for _, pool := range m.pools {
+		pools = append(pools, *copyPool(pool))
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].PoolName < pools[j].PoolName
+	})
+	return pools, nil
+}
+
+func (m *MemoryStorage) DeleteReviewerPool(ctx context.Context, poolName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.pools[poolName]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.pools, poolName)
+
+	for _, t := range m.teams {
+		kept := []string{}
+		for _, name := range t.settings.FallbackPools {
+			if name != poolName {
+				kept = append(kept, name)
+			}
+		}
+		t.settings.FallbackPools = kept
+	}
+	return nil
+}
+
+func (m *MemoryStorage) GetActivePoolMembers(ctx context.Context, poolName, excludeUserID string, at time.Time) ([]model.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := []model.User{}
+	pool, ok := m.pools[poolName]
+	if !ok {
+		return users, nil
+	}
+
+	away := m.unavailableOn(model.FormatDate(at))
+	for _, userID := range pool.Members {
+		u, ok := m.users[userID]
+		if ok && u.user.IsActive && userID != excludeUserID && !away[userID] {
+			users = append(users, u.user)
+		}
+	}
+	return users, nil
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (m *MemoryStorage) ListOverdueAssignments(ctx context.Context, now time.Time) ([]model.OverdueAssignment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	overdue := []model.OverdueAssignment{}
+	for _, p := range m.prs {
+		if p.pr.Status != model.StatusOpen {
+			continue
+		}
+		teamName := m.users[p.pr.AuthorID].user.TeamName
+		team, ok := m.teams[teamName]
+		if !ok || team.settings.ReviewSLAMinutes <= 0 {
+			continue
+		}
+		sla := time.Duration(team.settings.ReviewSLAMinutes) * time.Minute
+
+		for _, reviewerID := range p.reviewers {
+			assignedAt := p.assignedAt[reviewerID]
+			if assignedAt.Add(sla).After(now) || hasVerdictSince(p, reviewerID, assignedAt) ||
+				m.hasSLABreach(p.pr.PullRequestID, reviewerID, assignedAt) {
+				continue
+			}
+			overdue = append(overdue, model.OverdueAssignment{
+				PullRequestID: p.pr.PullRequestID,
+				ReviewerID:    reviewerID,
+				TeamName:      teamName,
+				AssignedAt:    assignedAt,
+				SLAMinutes:    team.settings.ReviewSLAMinutes,
+			})
+		}
+	}
+	sort.Slice(overdue, func(i, j int) bool {
+		a, b := overdue[i], overdue[j]
+		if !a.AssignedAt.Equal(b.AssignedAt) {
+			return a.AssignedAt.Before(b.AssignedAt)
+		}
+		if a.PullRequestID != b.PullRequestID {
+			return a.PullRequestID < b.PullRequestID
+		}
+		return a.ReviewerID < b.ReviewerID
+	})
+	return overdue, nil
+}
+
+// hasVerdictSince reports whether reviewerID approved or requested changes
+// on the PR at or after since. Review times only have second precision, so
+// since is truncated to match. Callers must hold m.mu.
+func hasVerdictSince(p *memPR, reviewerID string, since time.Time) bool {
+	since = since.Truncate(time.Second)
+	for _, r := range p.reviews {
+		if r.UserID != reviewerID || r.Verdict == model.VerdictCommented {
+			continue
+		}
+		submittedAt, err := time.Parse(time.RFC3339, r.SubmittedAt)
+		if err == nil && !submittedAt.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSLABreach reports whether the assignment already has a recorded
+// breach. Callers must hold m.mu.
+func (m *MemoryStorage) hasSLABreach(prID, reviewerID string, assignedAt time.Time) bool {
+	formatted := model.FormatTime(assignedAt)
+	for _, b := range m.slaBreaches {
+		if b.PullRequestID == prID && b.ReviewerID == reviewerID && b.AssignedAt == formatted {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryStorage) RecordSLABreach(ctx context.Context, a model.OverdueAssignment, action string, newReviewer *model.ReviewerSource, teamLeadID string, events ...model.Event) (*model.SLABreach, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.hasSLABreach(a.PullRequestID, a.ReviewerID, a.AssignedAt) {
+		return nil, sql.ErrNoRows
+	}
+	p, ok := m.prs[a.PullRequestID]
+	if !ok || p.pr.Status != model.StatusOpen {
+		return nil, sql.ErrNoRows
+	}
+	if assignedAt, ok := p.assignedAt[a.ReviewerID]; !ok || !assignedAt.Equal(a.AssignedAt) {
+		return nil, sql.ErrNoRows
+	}
+	if newReviewer != nil {
+		if err := m.reassignReviewer(a.PullRequestID, a.ReviewerID, *newReviewer, model.ReasonSLA, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	m.lastID++
+	breach := model.SLABreach{
+		ID:            m.lastID,
+		PullRequestID: a.PullRequestID,
+		ReviewerID:    a.ReviewerID,
+		TeamName:      a.TeamName,
+		AssignedAt:    model.FormatTime(a.AssignedAt),
+		SLAMinutes:    a.SLAMinutes,
+		Action:        action,
+		DetectedAt:    model.FormatTime(time.Now()),
+	}
+	if newReviewer != nil {
+		newReviewerID := newReviewer.UserID
+		breach.NewReviewerID = &newReviewerID
+	}
+	if teamLeadID != "" {
+		breach.TeamLeadID = &teamLeadID
+	}
+	m.slaBreaches = append(m.slaBreaches, breach)
+
+	if err := m.appendOutbox(ctx, events); err != nil {
+		return nil, err
+	}
+	return &breach, nil
+}
+
+func (m *MemoryStorage) ListSLABreaches(ctx context.Context, teamName string, limit int) ([]model.SLABreach, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	breaches := []model.SLABreach{}
+	for i := len(m.slaBreaches) - 1; i >= 0 && len(breaches) < limit; i-- {
+		if teamName == "" || m.slaBreaches[i].TeamName == teamName {
+			breaches = append(breaches, m.slaBreaches[i])
+		}
+	}
+	return breaches, nil
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (m *MemoryStorage) GetStatistics(ctx context.Context, filter model.StatisticsFilter) (*model.Statistics, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := &model.Statistics{
+		Reassignments: model.ReassignmentStat{ByReason: map[string]int{}},
+	}
+	inWindow := func(t time.Time) bool {
+		return (filter.From == nil || !t.Before(*filter.From)) && (filter.To == nil || t.Before(*filter.To))
+	}
+
+	durations := []float64{}
+	counts := make(map[string]int)
+	for _, p := range m.prs {
+		if filter.TeamName != "" && m.users[p.pr.AuthorID].user.TeamName != filter.TeamName {
+			continue
+		}
+
+		if inWindow(p.createdAt) {
+			stats.TotalPRs++
+			switch p.pr.Status {
+			case model.StatusDraft:
+				stats.DraftPRs++
+			case model.StatusOpen:
+				stats.OpenPRs++
+			case model.StatusMerged:
+				stats.MergedPRs++
+			case model.StatusClosed:
+				stats.ClosedPRs++
+			}
+		}
+		if p.pr.Status == model.StatusMerged && p.mergedAt != nil && inWindow(*p.mergedAt) {
+			durations = append(durations, p.mergedAt.Sub(p.createdAt).Seconds())
+		}
+		for _, reviewerID := range p.reviewers {
+			if inWindow(p.assignedAt[reviewerID]) {
+				counts[reviewerID]++
+			}
+		}
+		for _, a := range p.history {
+			createdAt, err := time.Parse(time.RFC3339, a.CreatedAt)
+			if err == nil && a.Action == model.AssignmentReassigned && inWindow(createdAt) {
+				stats.Reassignments.ByReason[a.Reason]++
+				stats.Reassignments.Total++
+			}
+		}
+	}
+
+	sort.Float64s(durations)
+	stats.TimeToMerge.Count = len(durations)
+	if len(durations) > 0 {
+		median, p90 := percentile(durations, 0.5), percentile(durations, 0.9)
+		stats.TimeToMerge.MedianSeconds = &median
+		stats.TimeToMerge.P90Seconds = &p90
+	}
+
+	userIDs := make([]string, 0, len(counts))
+	for userID := range counts {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		if counts[userIDs[i]] != counts[userIDs[j]] {
+			return counts[userIDs[i]] > counts[userIDs[j]]
+		}
+		return userIDs[i] < userIDs[j]
+	})
+	stats.TopReviewers = []model.ReviewerStat{}
+	for _, userID := range userIDs {
+		if len(stats.TopReviewers) == filter.Limit {
+			break
+		}
+		stats.TopReviewers = append(stats.TopReviewers, model.ReviewerStat{
+			UserID:      userID,
+			Username:    m.users[userID].user.Username,
+			ReviewCount: counts[userID],
+		})
+	}
+
+	stats.OpenReviewLoad = m.openReviewLoad(filter)
+	return stats, nil
+}
+
+// openReviewLoad mirrors Storage.openReviewLoad. Callers must hold m.mu.
+func (m *MemoryStorage) openReviewLoad(filter model.StatisticsFilter) []model.ReviewLoad {
+	open := make(map[string]int)
+	for _, p := range m.prs {
+		if p.pr.Status == model.StatusOpen {
+			for _, reviewerID := range p.reviewers {
+				open[reviewerID]++
+			}
+		}
+	}
+
+	load := []model.ReviewLoad{}
+	for _, u := range m.sortedUsers() {
+		if u.user.IsActive && (filter.TeamName == "" || u.user.TeamName == filter.TeamName) {
+			load = append(load, model.ReviewLoad{
+				UserID:      u.user.UserID,
+				Username:    u.user.Username,
+				TeamName:    u.user.TeamName,
+				OpenReviews: open[u.user.UserID],
+			})
+		}
+	}
+	sort.SliceStable(load, func(i, j int) bool {
+		return load[i].OpenReviews > load[j].OpenReviews
+	})
+	if len(load) > filter.Limit {
+		load = load[:filter.Limit]
+	}
+	return load
+}
+
+// percentile interpolates linearly between the closest ranks of sorted,
+// like PostgreSQL's percentile_cont.
+func percentile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+func (m *MemoryStorage) GetAssignmentCounts(ctx context.Context, userIDs []string, from, to *time.Time) (map[string]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int, len(userIDs))
+	wanted := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		wanted[userID] = true
+	}
+	for _, p := range m.prs {
+		for _, a := range p.history {
+			if !wanted[a.UserID] || a.Action == model.AssignmentUnassigned {
+				continue
+			}
+			createdAt, err := time.Parse(time.RFC3339, a.CreatedAt)
+			if err != nil || (from != nil && createdAt.Before(*from)) || (to != nil && !createdAt.Before(*to)) {
+				continue
+			}
+			counts[a.UserID]++
+		}
+	}
+	return counts, nil
+}
+
+func (m *MemoryStorage) GetTeamLoad(ctx context.Context) ([]model.TeamLoad, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	load := make(map[string]*model.TeamLoad, len(m.teams))
+	for teamName := range m.teams {
+		load[teamName] = &model.TeamLoad{TeamName: teamName}
+	}
+	for _, p := range m.prs {
+		if p.pr.Status != model.StatusOpen {
+			continue
+		}
+		if t, ok := load[m.users[p.pr.AuthorID].user.TeamName]; ok {
+			t.OpenPRs++
+		}
+		for _, reviewerID := range p.reviewers {
+			if t, ok := load[m.users[reviewerID].user.TeamName]; ok {
+				t.OpenReviews++
+			}
+		}
+	}
+
+	teams := make([]model.TeamLoad, 0, len(load))
+	for _, t := range load {
+		teams = append(teams, *t)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].TeamName < teams[j].TeamName
+	})
+	return teams, nil
+}
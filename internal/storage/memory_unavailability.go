@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (m *MemoryStorage) CreateUnavailability(ctx context.Context, u model.Unavailability) (*model.Unavailability, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[u.UserID]; !ok {
+		return nil, fmt.Errorf("user %q does not exist", u.UserID)
+	}
+
+	m.lastID++
+	u.ID = m.lastID
+	u.CreatedAt = model.FormatTime(time.Now())
+	m.unavailability = append(m.unavailability, u)
+	return &u, nil
+}
+
+func (m *MemoryStorage) ListUnavailability(ctx context.Context, userID string) ([]model.Unavailability, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	windows := []model.Unavailability{}
+	for _, u := range m.unavailability {
+		if u.UserID == userID {
+			windows = append(windows, u)
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].StartDate < windows[j].StartDate
+	})
+	return windows, nil
+}
+
+func (m *MemoryStorage) DeleteUnavailability(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, u := range m.unavailability {
+		if u.ID == id {
+			m.unavailability = append(m.unavailability[:i], m.unavailability[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *MemoryStorage) GetUnavailableUserIDs(ctx context.Context, day time.Time) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	away := m.unavailableOn(model.FormatDate(day))
+	userIDs := []string{}
+	for _, u := range m.sortedUsers() {
+		if u.user.IsActive && away[u.user.UserID] {
+			userIDs = append(userIDs, u.user.UserID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (m *MemoryStorage) ListUnreassignedUnavailability(ctx context.Context, day time.Time) ([]model.Unavailability, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	date := model.FormatDate(day)
+	windows := []model.Unavailability{}
+	for _, u := range m.unavailability {
+		if u.ReviewsReassignedAt == nil && u.StartDate <= date && date <= u.EndDate && m.users[u.UserID].user.IsActive {
+			windows = append(windows, u)
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool {
+		if windows[i].UserID != windows[j].UserID {
+			return windows[i].UserID < windows[j].UserID
+		}
+		return windows[i].StartDate < windows[j].StartDate
+	})
+	return windows, nil
+}
+
+func (m *MemoryStorage) MarkUnavailabilityReassigned(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, u := range m.unavailability {
+		if u.ID == id && u.ReviewsReassignedAt == nil {
+			at := model.FormatTime(time.Now())
+			m.unavailability[i].ReviewsReassignedAt = &at
+		}
+	}
+	return nil
+}
+
+// unavailableOn returns the set of users with a window covering date, which
+// is formatted as model.DateLayout. Callers must hold m.mu.
+func (m *MemoryStorage) unavailableOn(date string) map[string]bool {
+	away := make(map[string]bool)
+	for _, u := range m.unavailability {
+		// DateLayout strings order the same way as the dates they encode.
+		if u.StartDate <= date && date <= u.EndDate {
+			away[u.UserID] = true
+		}
+	}
+	return away
+}
@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+type memDelivery struct {
+	delivery      model.WebhookDelivery
+	nextAttemptAt time.Time
+	payload       []byte
+	traceParent   string
+}
+
+func (m *MemoryStorage) CreateWebhookSubscription(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	sub.ID = m.lastID
+	sub.EventTypes = append([]string{}, sub.EventTypes...)
+	sub.CreatedAt = model.FormatTime(time.Now())
+	m.subscriptions = append(m.subscriptions, sub)
+	return &sub, nil
+}
+
+func (m *MemoryStorage) ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subs := make([]model.WebhookSubscription, len(m.subscriptions))
+	copy(subs, m.subscriptions)
+	return subs, nil
+}
+
+func (m *MemoryStorage) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, sub := range m.subscriptions {
+		if sub.ID != id {
+			continue
+		}
+		m.subscriptions = append(m.subscriptions[:i], m.subscriptions[i+1:]...)
+
+		deliveries := m.deliveries[:0]
+		removed := make(map[int64]bool)
+		for _, d := range m.deliveries {
+			if d.delivery.SubscriptionID == id {
+				removed[d.delivery.ID] = true
+				continue
+			}
+			deliveries = append(deliveries, d)
+		}
+		m.deliveries = deliveries
+
+		attempts := m.attempts[:0]
+		for _, a := range m.attempts {
+			if !removed[a.DeliveryID] {
+				attempts = append(attempts, a)
+			}
+		}
+		m.attempts = attempts
+
+		letters := m.deadLetters[:0]
+		for _, l := range m.deadLetters {
+			if !removed[l.DeliveryID] {
+				letters = append(letters, l)
+			}
+		}
+		m.deadLetters = letters
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (m *MemoryStorage) EnqueueWebhookEvent(ctx context.Context, event model.Event) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	created := 0
+	for _, sub := range m.subscriptions {
+		if !sub.IsActive || !subscribedTo(sub, event.Type) || m.hasDelivery(sub.ID, event.ID) {
+			continue
+		}
+		m.lastID++
+		m.deliveries = append(m.deliveries, &memDelivery{
+			delivery: model.WebhookDelivery{
+				ID:             m.lastID,
+				SubscriptionID: sub.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Status:         model.DeliveryPending,
+				CreatedAt:      model.FormatTime(now),
+			},
+			nextAttemptAt: now,
+			payload:       payload,
+			traceParent:   tracing.TraceParent(ctx),
+		})
+		created++
+	}
+	return created, nil
+}
+
+func (m *MemoryStorage) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.PendingWebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	due := []*memDelivery{}
+	for _, d := range m.deliveries {
+		if d.delivery.Status == model.DeliveryPending && !d.nextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].nextAttemptAt.Before(due[j].nextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := []model.PendingWebhookDelivery{}
+	for _, d := range due {
+		sub := m.findSubscription(d.delivery.SubscriptionID)
+		if sub == nil {
+			continue
+		}
+		pending := model.PendingWebhookDelivery{
+			WebhookDelivery: d.delivery,
+			URL:             sub.URL,
+			Secret:          sub.Secret,
+			Payload:         d.payload,
+			TraceParent:     d.traceParent,
+		}
+		pending.NextAttemptAt = model.FormatTime(d.nextAttemptAt)
+		claimed = append(claimed, pending)
+
+		d.nextAttemptAt = now.Add(lease)
+	}
+	return claimed, nil
+}
+
+func (m *MemoryStorage) RecordWebhookAttempt(ctx context.Context, deliveryID int64, result model.WebhookAttemptResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var d *memDelivery
+	for _, candidate := range m.deliveries {
+		if candidate.delivery.ID == deliveryID {
+			d = candidate
+			break
+		}
+	}
+	if d == nil {
+		return sql.ErrNoRows
+	}
+
+	m.lastID++
+	m.attempts = append(m.attempts, model.WebhookDeliveryAttempt{
+		ID:          m.lastID,
+		DeliveryID:  deliveryID,
+		Attempt:     result.Attempt,
+		StatusCode:  result.StatusCode,
+		Error:       result.Error,
+		DurationMs:  result.Duration.Milliseconds(),
+		AttemptedAt: model.FormatTime(result.AttemptedAt),
+	})
+
+	d.delivery.Status = result.Status
+	d.delivery.Attempts = result.Attempt
+	d.delivery.LastError = result.Error
+	d.nextAttemptAt = result.NextAttemptAt
+	if result.Status == model.DeliveryDelivered {
+		deliveredAt := model.FormatTime(result.AttemptedAt)
+		d.delivery.DeliveredAt = &deliveredAt
+	}
+
+	if result.Status == model.DeliveryDead {
+		m.deadLetters = append(m.deadLetters, model.WebhookDeadLetter{
+			DeliveryID:     deliveryID,
+			SubscriptionID: d.delivery.SubscriptionID,
+			EventType:      d.delivery.EventType,
+			Payload:        string(d.payload),
+			Attempts:       result.Attempt,
+			LastError:      result.Error,
+			FailedAt:       model.FormatTime(result.AttemptedAt),
+		})
+	}
+	return nil
+}
+
+func (m *MemoryStorage) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]model.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	deliveries := []model.WebhookDelivery{}
+	for i := len(m.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		d := m.deliveries[i]
+		if subscriptionID != 0 && d.delivery.SubscriptionID != subscriptionID {
+			continue
+		}
+		if status != "" && d.delivery.Status != status {
+			continue
+		}
+		delivery := d.delivery
+		delivery.NextAttemptAt = model.FormatTime(d.nextAttemptAt)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (m *MemoryStorage) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]model.WebhookDeliveryAttempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	attempts := []model.WebhookDeliveryAttempt{}
+	for _, a := range m.attempts {
+		if a.DeliveryID == deliveryID {
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts, nil
+}
+
+func (m *MemoryStorage) ListWebhookDeadLetters(ctx context.Context, limit int) ([]model.WebhookDeadLetter, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	letters := []model.WebhookDeadLetter{}
+	for i := len(m.deadLetters) - 1; i >= 0 && len(letters) < limit; i-- {
+		letters = append(letters, m.deadLetters[i])
+	}
+	return letters, nil
+}
+
+// hasDelivery reports whether event was already queued for a subscription.
+// Callers must hold m.mu.
+func (m *MemoryStorage) hasDelivery(subscriptionID int64, eventID string) bool {
+	for _, d := range m.deliveries {
+		if d.delivery.SubscriptionID == subscriptionID && d.delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// findSubscription looks a subscription up by id. Callers must hold m.mu.
+func (m *MemoryStorage) findSubscription(id int64) *model.WebhookSubscription {
+	for i := range m.subscriptions {
+		if m.subscriptions[i].ID == id {
+			return &m.subscriptions[i]
+		}
+	}
+	return nil
+}
+
+func subscribedTo(sub model.WebhookSubscription, eventType string) bool {
+	if len(sub.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range sub.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+// insertOutbox stores events in the outbox as part of tx, so they are
+// published if and only if the state change they describe is committed.
+// The trace of ctx is stored with them so that publishing joins it.
+func insertOutbox(ctx context.Context, tx *sql.Tx, events []model.Event) error {
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO outbox (event_id, event_type, payload, next_attempt_at, trace_parent)
+			VALUES ($1, $2, $3, $4, $5)`,
+			event.ID, event.Type, string(payload), time.Now().UTC(), tracing.TraceParent(ctx))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimOutboxEvents returns up to limit unpublished events that are due at
+// now, oldest first, and leases them so concurrent relays skip them.
+func (s *Storage) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEntry, error) {
+	ctx, done := observe(ctx, "ClaimOutboxEvents")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, payload, attempts, trace_parent
+		FROM outbox
+		WHERE published_at IS NULL AND next_attempt_at <= $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []model.OutboxEntry{}
+	for rows.Next() {
+		var entry model.OutboxEntry
+		var payload string
+		if err := rows.Scan(&entry.ID, &payload, &entry.Attempts, &entry.TraceParent); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(payload), &entry.Event); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	rows.Close()
+
+	for _, entry := range entries {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE outbox SET next_attempt_at = $1
+			WHERE id = $2`, now.Add(lease).UTC(), entry.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, tx.Commit()
+}
+
+func (s *Storage) MarkOutboxPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	ctx, done := observe(ctx, "MarkOutboxPublished")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox SET published_at = $1, attempts = attempts + 1, last_error = NULL
+		WHERE id = $2`, publishedAt.UTC(), id)
+	return err
+}
+
+func (s *Storage) MarkOutboxFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time) error {
+	ctx, done := observe(ctx, "MarkOutboxFailed")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+		WHERE id = $3`, errMsg, nextAttemptAt.UTC(), id)
+	return err
+}
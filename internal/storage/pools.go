@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (s *Storage) CreateReviewerPool(ctx context.Context, pool model.ReviewerPool) (*model.ReviewerPool, error) {
+	ctx, done := observe(ctx, "CreateReviewerPool")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO reviewer_pools (pool_name) VALUES ($1)
+		RETURNING created_at`, pool.PoolName).Scan(&createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertPoolMembers(ctx, tx, pool.PoolName, pool.Members); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	pool.CreatedAt = model.FormatTime(createdAt)
+	return &pool, nil
+}
+
+// SetReviewerPoolMembers replaces the members of poolName. It returns
+// sql.ErrNoRows if the pool does not exist.
+func (s *Storage) SetReviewerPoolMembers(ctx context.Context, poolName string, userIDs []string) error {
+	ctx, done := observe(ctx, "SetReviewerPoolMembers")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking the pool row serializes concurrent member updates.
+	err = tx.QueryRowContext(ctx, `
+		SELECT pool_name FROM reviewer_pools WHERE pool_name = $1 FOR UPDATE`, poolName).Scan(&poolName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reviewer_pool_members WHERE pool_name = $1`, poolName); err != nil {
+		return err
+	}
+	if err := insertPoolMembers(ctx, tx, poolName, userIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertPoolMembers(ctx context.Context, tx *sql.Tx, poolName string, userIDs []string) error {
+	for _, userID := range userIDs {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO reviewer_pool_members (pool_name, user_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`, poolName, userID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) GetReviewerPool(ctx context.Context, poolName string) (*model.ReviewerPool, error) {
+	ctx, done := observe(ctx, "GetReviewerPool")
+	defer done()
+
+	pools, err := s.listReviewerPools(ctx, `WHERE p.pool_name = $1`, poolName)
+	if err != nil || len(pools) == 0 {
+		return nil, err
+	}
+	return &pools[0], nil
+}
+
+func (s *Storage) ListReviewerPools(ctx context.Context) ([]model.ReviewerPool, error) {
+	ctx, done := observe(ctx, "ListReviewerPools")
+	defer done()
+
+	return s.listReviewerPools(ctx, "")
+}
+
+func (s *Storage) listReviewerPools(ctx context.Context, where string, args ...interface{}) ([]model.ReviewerPool, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.pool_name, p.created_at, m.user_id
+		FROM reviewer_pools p
+		LEFT JOIN reviewer_pool_members m ON m.pool_name = p.pool_name
+		`+where+`
+		ORDER BY p.pool_name, m.user_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pools := []model.ReviewerPool{}
+	for rows.Next() {
+		var poolName string
+		var createdAt time.Time
+		var userID sql.NullString
+		if err := rows.Scan(&poolName, &createdAt, &userID); err != nil {
+			return nil, err
+		}
+		if len(pools) == 0 || pools[len(pools)-1].PoolName != poolName {
+			pools = append(pools, model.ReviewerPool{
+				PoolName:  poolName,
+				Members:   []string{},
+				CreatedAt: model.FormatTime(createdAt),
+			})
+		}
+		if userID.Valid {
+			pool := &pools[len(pools)-1]
+			pool.Members = append(pool.Members, userID.String)
+		}
+	}
+	return pools, rows.Err()
+}
+
+// DeleteReviewerPool removes the pool and drops it from the fallback pools
+// of every team that referenced it.
+func (s *Storage) DeleteReviewerPool(ctx context.Context, poolName string) error {
+	ctx, done := observe(ctx, "DeleteReviewerPool")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM reviewer_pools WHERE pool_name = $1`, poolName)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE teams
+		SET fallback_pools = array_to_string(array_remove(string_to_array(fallback_pools, ','), $1), ',')
+		WHERE $1 = ANY(string_to_array(fallback_pools, ','))`, poolName)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetActivePoolMembers returns the active members of poolName other than
+// excludeUserID that are not out of office on the date of at.
+func (s *Storage) GetActivePoolMembers(ctx context.Context, poolName, excludeUserID string, at time.Time) ([]model.User, error) {
+	ctx, done := observe(ctx, "GetActivePoolMembers")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.user_id, u.username, u.team_name, u.is_active, u.max_open_reviews
+		FROM reviewer_pool_members m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.pool_name = $1 AND u.is_active = true AND u.user_id != $2
+			AND NOT EXISTS (
+				SELECT 1 FROM user_unavailability w
+				WHERE w.user_id = u.user_id AND $3::DATE BETWEEN w.start_date AND w.end_date
+			)
+		ORDER BY u.user_id`, poolName, excludeUserID, model.FormatDate(at))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
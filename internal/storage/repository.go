@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+// Repository describes the persistence operations the service layer relies on.
+// Both the PostgreSQL Storage and the in-memory MemoryStorage implement it.
+type Repository interface {
+	Close() error
+	Ping(ctx context.Context) error
+
+	CreateTeam(ctx context.Context, teamName string, settings model.TeamSettings) error
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+	GetTeam(ctx context.Context, teamName string) (*model.Team, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*model.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, teamName string, settings model.TeamSettings) error
+	DeactivateTeam(ctx context.Context, teamName string, eventsFor func(deactivatedUserIDs []string) []model.Event) ([]string, error)
+	SetTeamCodeOwners(ctx context.Context, teamName, content string) (*model.CodeOwners, error)
+	GetTeamCodeOwners(ctx context.Context, teamName string) (*model.CodeOwners, error)
+
+	CreateReviewerPool(ctx context.Context, pool model.ReviewerPool) (*model.ReviewerPool, error)
+	SetReviewerPoolMembers(ctx context.Context, poolName string, userIDs []string) error
+	GetReviewerPool(ctx context.Context, poolName string) (*model.ReviewerPool, error)
+	ListReviewerPools(ctx context.Context) ([]model.ReviewerPool, error)
+	DeleteReviewerPool(ctx context.Context, poolName string) error
+	GetActivePoolMembers(ctx context.Context, poolName, excludeUserID string, at time.Time) ([]model.User, error)
+
+	UpsertUser(ctx context.Context, user model.User) error
+	GetUser(ctx context.Context, userID string) (*model.User, error)
+	SetUserActive(ctx context.Context, userID string, isActive bool) error
+	SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) error
+	LinkExternalAccount(ctx context.Context, account model.ExternalAccount) error
+	GetUserByExternalLogin(ctx context.Context, provider, login string) (*model.User, error)
+	GetActiveTeamMembers(ctx context.Context, teamName, excludeUserID string, at time.Time) ([]model.User, error)
+
+	CreatePR(ctx context.Context, pr model.PullRequest, events ...model.Event) error
+	PRExists(ctx context.Context, prID string) (bool, error)
+	GetPR(ctx context.Context, prID string) (*model.PullRequest, error)
+	MergePR(ctx context.Context, prID string, override *model.MergeOverride, events ...model.Event) error
+	ListMergeOverrides(ctx context.Context, limit int) ([]model.MergeOverride, error)
+	TransitionPR(ctx context.Context, prID, from, to string, reviewersRequired int, reviewers []model.ReviewerSource, events ...model.Event) error
+	ReassignReviewer(ctx context.Context, prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string, events ...model.Event) error
+	ListReviewerAssignments(ctx context.Context, prID string) ([]model.ReviewerAssignment, error)
+
+	CreateUnavailability(ctx context.Context, u model.Unavailability) (*model.Unavailability, error)
+	ListUnavailability(ctx context.Context, userID string) ([]model.Unavailability, error)
+	DeleteUnavailability(ctx context.Context, id int64) error
+	GetUnavailableUserIDs(ctx context.Context, day time.Time) ([]string, error)
+	ListUnreassignedUnavailability(ctx context.Context, day time.Time) ([]model.Unavailability, error)
+	MarkUnavailabilityReassigned(ctx context.Context, id int64) error
+	AddReview(ctx context.Context, review model.Review, events ...model.Event) (*model.Review, error)
+	GetPRsByReviewer(ctx context.Context, userID string) ([]model.PullRequestShort, error)
+	GetOpenPRsForReviewers(ctx context.Context, userIDs []string) ([]string, error)
+	GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+	GetLastAssignedReviewer(ctx context.Context, userIDs []string) (string, error)
+	ListUnderReviewedPRs(ctx context.Context, teamName string) ([]model.UnderReviewedPR, error)
+	ListOverdueAssignments(ctx context.Context, now time.Time) ([]model.OverdueAssignment, error)
+	RecordSLABreach(ctx context.Context, a model.OverdueAssignment, action string, newReviewer *model.ReviewerSource, teamLeadID string, events ...model.Event) (*model.SLABreach, error)
+	ListSLABreaches(ctx context.Context, teamName string, limit int) ([]model.SLABreach, error)
+
+	GetStatistics(ctx context.Context, filter model.StatisticsFilter) (*model.Statistics, error)
+	GetAssignmentCounts(ctx context.Context, userIDs []string, from, to *time.Time) (map[string]int, error)
+	GetTeamLoad(ctx context.Context) ([]model.TeamLoad, error)
+
+	CreateWebhookSubscription(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	EnqueueWebhookEvent(ctx context.Context, event model.Event) (int, error)
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.PendingWebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, deliveryID int64, result model.WebhookAttemptResult) error
+	ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]model.WebhookDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]model.WebhookDeliveryAttempt, error)
+	ListWebhookDeadLetters(ctx context.Context, limit int) ([]model.WebhookDeadLetter, error)
+
+	ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEntry, error)
+	MarkOutboxPublished(ctx context.Context, id int64, publishedAt time.Time) error
+	MarkOutboxFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time) error
+}
+
+var (
+	_ Repository = (*Storage)(nil)
+	_ Repository = (*MemoryStorage)(nil)
+)
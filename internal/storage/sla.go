@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+// ListOverdueAssignments returns the reviewer assignments on open PRs that
+// are older than the review SLA of the author's team at now and have not
+// received an APPROVED or CHANGES_REQUESTED review since they were made.
+// Assignments that already have a recorded breach are left out, and so
+// are teams without an SLA.
+func (s *Storage) ListOverdueAssignments(ctx context.Context, now time.Time) ([]model.OverdueAssignment, error) {
+	ctx, done := observe(ctx, "ListOverdueAssignments")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT r.pull_request_id, r.user_id, a.team_name, r.assigned_at, t.review_sla_minutes
+		FROM pr_reviewers r
+		JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+		JOIN users a ON a.user_id = p.author_id
+		JOIN teams t ON t.team_name = a.team_name
+		WHERE p.status = 'OPEN' AND t.review_sla_minutes > 0
+			AND r.assigned_at + t.review_sla_minutes * INTERVAL '1 minute' <= $1
+			AND NOT EXISTS (
+				SELECT 1 FROM pr_reviews v
+				WHERE v.pull_request_id = r.pull_request_id AND v.user_id = r.user_id
+					AND v.verdict <> $2 AND v.created_at >= r.assigned_at)
+			AND NOT EXISTS (
+				SELECT 1 FROM sla_breaches b
+				WHERE b.pull_request_id = r.pull_request_id AND b.reviewer_id = r.user_id
+					AND b.assigned_at = r.assigned_at)
+		ORDER BY r.assigned_at, r.pull_request_id, r.user_id`, now.UTC(), model.VerdictCommented)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overdue := []model.OverdueAssignment{}
+	for rows.Next() {
+		var a model.OverdueAssignment
+		if err := rows.Scan(&a.PullRequestID, &a.ReviewerID, &a.TeamName, &a.AssignedAt, &a.SLAMinutes); err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, a)
+	}
+	return overdue, rows.Err()
+}
+
+// RecordSLABreach stores how the overdue assignment a was handled together
+// with events. A non-nil newReviewer takes over the review with reason sla
+// in the same transaction, so a review is never moved without its breach
+// being recorded. An empty teamLeadID is stored as NULL. It returns
+// sql.ErrNoRows if the breach was already recorded, or if the assignment no
+// longer exists on an OPEN PR because the reviewer was replaced or the PR
+// left OPEN since a was listed, so that each assignment is handled once.
+func (s *Storage) RecordSLABreach(ctx context.Context, a model.OverdueAssignment, action string, newReviewer *model.ReviewerSource, teamLeadID string, events ...model.Event) (*model.SLABreach, error) {
+	ctx, done := observe(ctx, "RecordSLABreach")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	breach := model.SLABreach{
+		PullRequestID: a.PullRequestID,
+		ReviewerID:    a.ReviewerID,
+		TeamName:      a.TeamName,
+		AssignedAt:    model.FormatTime(a.AssignedAt),
+		SLAMinutes:    a.SLAMinutes,
+		Action:        action,
+	}
+	newReviewerID := ""
+	if newReviewer != nil {
+		newReviewerID = newReviewer.UserID
+		breach.NewReviewerID = &newReviewerID
+	}
+	if teamLeadID != "" {
+		breach.TeamLeadID = &teamLeadID
+	}
+	// Locking the assignment keeps it from being replaced until the breach
+	// is recorded.
+	var exists int
+	err = tx.QueryRowContext(ctx, `
+		SELECT 1
+		FROM pr_reviewers r
+		JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+		WHERE r.pull_request_id = $1 AND r.user_id = $2 AND r.assigned_at = $3 AND p.status = $4
+		FOR UPDATE OF r, p`, a.PullRequestID, a.ReviewerID, a.AssignedAt, model.StatusOpen).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+
+	var detectedAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO sla_breaches (pull_request_id, reviewer_id, team_name, assigned_at, sla_minutes,
+			action, new_reviewer_id, team_lead_id, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9)
+		ON CONFLICT (pull_request_id, reviewer_id, assigned_at) DO NOTHING
+		RETURNING id, detected_at`,
+		a.PullRequestID, a.ReviewerID, a.TeamName, a.AssignedAt, a.SLAMinutes,
+		action, newReviewerID, teamLeadID, time.Now().UTC()).Scan(&breach.ID, &detectedAt)
+	if err != nil {
+		return nil, err
+	}
+	breach.DetectedAt = model.FormatTime(detectedAt)
+
+	if newReviewer != nil {
+		err := reassignReviewer(ctx, tx, a.PullRequestID, a.ReviewerID, *newReviewer, model.ReasonSLA, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := insertOutbox(ctx, tx, events); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &breach, nil
+}
+
+// ListSLABreaches returns up to limit recorded breaches, newest first. An
+// empty teamName lists breaches of every team.
+func (s *Storage) ListSLABreaches(ctx context.Context, teamName string, limit int) ([]model.SLABreach, error) {
+	ctx, done := observe(ctx, "ListSLABreaches")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, pull_request_id, reviewer_id, team_name, assigned_at, sla_minutes,
+			action, new_reviewer_id, team_lead_id, detected_at
+		FROM sla_breaches
+		WHERE $1 = '' OR team_name = $1
+		ORDER BY id DESC
+		LIMIT $2`, teamName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breaches := []model.SLABreach{}
+	for rows.Next() {
+		var b model.SLABreach
+		var assignedAt, detectedAt time.Time
+		var newReviewerID, teamLeadID sql.NullString
+		if err := rows.Scan(&b.ID, &b.PullRequestID, &b.ReviewerID, &b.TeamName, &assignedAt, &b.SLAMinutes,
+			&b.Action, &newReviewerID, &teamLeadID, &detectedAt); err != nil {
+			return nil, err
+		}
+		b.AssignedAt = model.FormatTime(assignedAt)
+		b.DetectedAt = model.FormatTime(detectedAt)
+		if newReviewerID.Valid {
+			b.NewReviewerID = &newReviewerID.String
+		}
+		if teamLeadID.Valid {
+			b.TeamLeadID = &teamLeadID.String
+		}
+		breaches = append(breaches, b)
+	}
+	return breaches, rows.Err()
+}
@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+// GetStatistics computes the statistics described by model.Statistics.
+// PRs belong to a team through their author; open review load is listed
+// for active users of the team, including those without open reviews.
+func (s *Storage) GetStatistics(ctx context.Context, filter model.StatisticsFilter) (*model.Statistics, error) {
+	ctx, done := observe(ctx, "GetStatistics")
+	defer done()
+
+	stats := &model.Statistics{
+		Reassignments: model.ReassignmentStat{ByReason: map[string]int{}},
+	}
+	args := []interface{}{filter.TeamName, filter.From, filter.To}
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE p.status = 'DRAFT') as draft,
+			COUNT(*) FILTER (WHERE p.status = 'OPEN') as open,
+			COUNT(*) FILTER (WHERE p.status = 'MERGED') as merged,
+			COUNT(*) FILTER (WHERE p.status = 'CLOSED') as closed
+		FROM pull_requests p
+		JOIN users a ON a.user_id = p.author_id
+		WHERE ($1 = '' OR a.team_name = $1)
+			AND ($2::TIMESTAMP IS NULL OR p.created_at >= $2::TIMESTAMP)
+			AND ($3::TIMESTAMP IS NULL OR p.created_at < $3::TIMESTAMP)`, args...).
+		Scan(&stats.TotalPRs, &stats.DraftPRs, &stats.OpenPRs, &stats.MergedPRs, &stats.ClosedPRs)
+	if err != nil {
+		return nil, err
+	}
+
+	var median, p90 sql.NullFloat64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM p.merged_at - p.created_at)),
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM p.merged_at - p.created_at))
+		FROM pull_requests p
+		JOIN users a ON a.user_id = p.author_id
+		WHERE p.status = 'MERGED' AND p.merged_at IS NOT NULL
+			AND ($1 = '' OR a.team_name = $1)
+			AND ($2::TIMESTAMP IS NULL OR p.merged_at >= $2::TIMESTAMP)
+			AND ($3::TIMESTAMP IS NULL OR p.merged_at < $3::TIMESTAMP)`, args...).
+		Scan(&stats.TimeToMerge.Count, &median, &p90)
+	if err != nil {
+		return nil, err
+	}
+	if median.Valid {
+		stats.TimeToMerge.MedianSeconds = &median.Float64
+		stats.TimeToMerge.P90Seconds = &p90.Float64
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ra.reason, COUNT(*)
+		FROM reviewer_assignments ra
+		JOIN pull_requests p ON p.pull_request_id = ra.pull_request_id
+		JOIN users a ON a.user_id = p.author_id
+		WHERE ra.action = 'reassigned'
+			AND ($1 = '' OR a.team_name = $1)
+			AND ($2::TIMESTAMP IS NULL OR ra.created_at >= $2::TIMESTAMP)
+			AND ($3::TIMESTAMP IS NULL OR ra.created_at < $3::TIMESTAMP)
+		GROUP BY ra.reason`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var reason string
+		var count int
+		if err := rows.Scan(&reason, &count); err != nil {
+			return nil, err
+		}
+		stats.Reassignments.ByReason[reason] = count
+		stats.Reassignments.Total += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if stats.TopReviewers, err = s.topReviewers(ctx, filter); err != nil {
+		return nil, err
+	}
+	if stats.OpenReviewLoad, err = s.openReviewLoad(ctx, filter); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *Storage) topReviewers(ctx context.Context, filter model.StatisticsFilter) ([]model.ReviewerStat, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.user_id, u.username, COUNT(*) as review_count
+		FROM pr_reviewers r
+		JOIN users u ON u.user_id = r.user_id
+		JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+		JOIN users a ON a.user_id = p.author_id
+		WHERE ($1 = '' OR a.team_name = $1)
+			AND ($2::TIMESTAMP IS NULL OR r.assigned_at >= $2::TIMESTAMP)
+			AND ($3::TIMESTAMP IS NULL OR r.assigned_at < $3::TIMESTAMP)
+		GROUP BY u.user_id, u.username
+		ORDER BY review_count DESC, u.user_id
+		LIMIT $4`, filter.TeamName, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviewers := []model.ReviewerStat{}
+	for rows.Next() {
+		var r model.ReviewerStat
+		if err := rows.Scan(&r.UserID, &r.Username, &r.ReviewCount); err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, r)
+	}
+	return reviewers, rows.Err()
+}
+
+func (s *Storage) openReviewLoad(ctx context.Context, filter model.StatisticsFilter) ([]model.ReviewLoad, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.user_id, u.username, u.team_name, COUNT(p.pull_request_id) as open_reviews
+		FROM users u
+		LEFT JOIN pr_reviewers r ON r.user_id = u.user_id
+		LEFT JOIN pull_requests p ON p.pull_request_id = r.pull_request_id AND p.status = 'OPEN'
+		WHERE u.is_active AND ($1 = '' OR u.team_name = $1)
+		GROUP BY u.user_id, u.username, u.team_name
+		ORDER BY open_reviews DESC, u.user_id
+		LIMIT $2`, filter.TeamName, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	load := []model.ReviewLoad{}
+	for rows.Next() {
+		var l model.ReviewLoad
+		if err := rows.Scan(&l.UserID, &l.Username, &l.TeamName, &l.OpenReviews); err != nil {
+			return nil, err
+		}
+		load = append(load, l)
+	}
+	return load, rows.Err()
+}
+
+// GetAssignmentCounts counts the assignments each of userIDs received in
+// the window from the reviewer history, including reassignments to them.
+// Users without assignments are absent from the result.
+func (s *Storage) GetAssignmentCounts(ctx context.Context, userIDs []string, from, to *time.Time) (map[string]int, error) {
+	ctx, done := observe(ctx, "GetAssignmentCounts")
+	defer done()
+
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	inClause, args := buildInClause(3, userIDs)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, COUNT(*)
+		FROM reviewer_assignments
+		WHERE action IN ('assigned', 'reassigned') AND user_id IN `+inClause+`
+			AND ($1::TIMESTAMP IS NULL OR created_at >= $1::TIMESTAMP)
+			AND ($2::TIMESTAMP IS NULL OR created_at < $2::TIMESTAMP)
+		GROUP BY user_id`, append([]interface{}{from, to}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	return counts, rows.Err()
+}
+
+// GetTeamLoad returns, for every team, the OPEN PRs authored by its members
+// and the reviews its members have on OPEN PRs.
+func (s *Storage) GetTeamLoad(ctx context.Context) ([]model.TeamLoad, error) {
+	ctx, done := observe(ctx, "GetTeamLoad")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT t.team_name,
+			(SELECT COUNT(*) FROM pull_requests p
+				JOIN users a ON a.user_id = p.author_id
+				WHERE p.status = 'OPEN' AND a.team_name = t.team_name),
+			(SELECT COUNT(*) FROM pr_reviewers r
+				JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+				JOIN users u ON u.user_id = r.user_id
+				WHERE p.status = 'OPEN' AND u.team_name = t.team_name)
+		FROM teams t
+		ORDER BY t.team_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []model.TeamLoad{}
+	for rows.Next() {
+		var t model.TeamLoad
+		if err := rows.Scan(&t.TeamName, &t.OpenPRs, &t.OpenReviews); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, rows.Err()
+}
@@ -1,21 +1,32 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"pr-reviewer-service/internal/metrics"
 	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	_ "github.com/lib/pq"
 )
 
+// Storage is the PostgreSQL Repository. Its timestamp columns carry no time
+// zone, so every time written to or compared with them is converted to UTC
+// first, and the session runs in UTC so column defaults agree.
 type Storage struct {
 	db *sql.DB
 }
 
 func New(host, port, user, password, dbname string) (*Storage, error) {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable timezone=UTC",
 		host, port, user, password, dbname)
 
 	db, err := sql.Open("postgres", connStr)
@@ -34,28 +45,67 @@ func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
-func (s *Storage) CreateTeam(teamName string) error {
-	_, err := s.db.Exec("INSERT INTO teams (team_name) VALUES ($1)", teamName)
+// Ping checks that the database is reachable.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// observe starts a span for a storage operation and times it for the
+// db_query_duration_seconds metric and the debug log. Use it as
+//
+//	ctx, done := observe(ctx, "GetPR")
+//	defer done()
+func observe(ctx context.Context, operation string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "postgresql")))
+	return ctx, func() {
+		elapsed := time.Since(start)
+		metrics.ObserveQuery(operation, elapsed)
+		slog.DebugContext(ctx, "DB query", "operation", operation,
+			"duration_ms", float64(elapsed.Microseconds())/1000)
+		span.End()
+	}
+}
+
+func (s *Storage) CreateTeam(ctx context.Context, teamName string, settings model.TeamSettings) error {
+	ctx, done := observe(ctx, "CreateTeam")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO teams (team_name, reviewer_strategy, reviewers_required, max_reviewers, approvals_required,
+			max_open_reviews, capacity_overflow, code_owners_mode, fallback_pools, understaffed_policy,
+			review_sla_minutes, sla_action, team_lead_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		teamName, settings.ReviewerStrategy, settings.ReviewersRequired, settings.MaxReviewers, settings.ApprovalsRequired,
+		settings.MaxOpenReviews, settings.CapacityOverflow, settings.CodeOwnersMode, strings.Join(settings.FallbackPools, ","),
+		settings.UnderstaffedPolicy, settings.ReviewSLAMinutes, settings.SLAAction, settings.TeamLeadID)
 	return err
 }
 
-func (s *Storage) TeamExists(teamName string) (bool, error) {
+func (s *Storage) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	ctx, done := observe(ctx, "TeamExists")
+	defer done()
+
 	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
 	return exists, err
 }
 
-func (s *Storage) GetTeam(teamName string) (*model.Team, error) {
-	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+func (s *Storage) GetTeam(ctx context.Context, teamName string) (*model.Team, error) {
+	ctx, done := observe(ctx, "GetTeam")
+	defer done()
+
+	settings, err := s.GetTeamSettings(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
+	if settings == nil {
 		return nil, nil
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT user_id, username, is_active 
 		FROM users 
 		WHERE team_name = $1
@@ -75,13 +125,67 @@ func (s *Storage) GetTeam(teamName string) (*model.Team, error) {
 	}
 
 	return &model.Team{
-		TeamName: teamName,
-		Members:  members,
+		TeamName:     teamName,
+		TeamSettings: *settings,
+		Members:      members,
 	}, nil
 }
 
-func (s *Storage) UpsertUser(user model.User) error {
-	_, err := s.db.Exec(`
+func (s *Storage) GetTeamSettings(ctx context.Context, teamName string) (*model.TeamSettings, error) {
+	ctx, done := observe(ctx, "GetTeamSettings")
+	defer done()
+
+	var settings model.TeamSettings
+	var fallbackPools string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT reviewer_strategy, reviewers_required, max_reviewers, approvals_required,
+			max_open_reviews, capacity_overflow, code_owners_mode, fallback_pools, understaffed_policy,
+			review_sla_minutes, sla_action, team_lead_id
+		FROM teams WHERE team_name = $1`, teamName).
+		Scan(&settings.ReviewerStrategy, &settings.ReviewersRequired, &settings.MaxReviewers, &settings.ApprovalsRequired,
+			&settings.MaxOpenReviews, &settings.CapacityOverflow, &settings.CodeOwnersMode, &fallbackPools,
+			&settings.UnderstaffedPolicy, &settings.ReviewSLAMinutes, &settings.SLAAction, &settings.TeamLeadID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	settings.FallbackPools = splitList(fallbackPools)
+	return &settings, nil
+}
+
+func (s *Storage) UpdateTeamSettings(ctx context.Context, teamName string, settings model.TeamSettings) error {
+	ctx, done := observe(ctx, "UpdateTeamSettings")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE teams
+		SET reviewer_strategy = $1, reviewers_required = $2, max_reviewers = $3, approvals_required = $4,
+			max_open_reviews = $5, capacity_overflow = $6, code_owners_mode = $7, fallback_pools = $8,
+			understaffed_policy = $9, review_sla_minutes = $10, sla_action = $11, team_lead_id = $12
+		WHERE team_name = $13`,
+		settings.ReviewerStrategy, settings.ReviewersRequired, settings.MaxReviewers, settings.ApprovalsRequired,
+		settings.MaxOpenReviews, settings.CapacityOverflow, settings.CodeOwnersMode, strings.Join(settings.FallbackPools, ","),
+		settings.UnderstaffedPolicy, settings.ReviewSLAMinutes, settings.SLAAction, settings.TeamLeadID, teamName)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *Storage) UpsertUser(ctx context.Context, user model.User) error {
+	ctx, done := observe(ctx, "UpsertUser")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO users (user_id, username, team_name, is_active, updated_at)
 		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (user_id) DO UPDATE SET
@@ -89,16 +193,19 @@ func (s *Storage) UpsertUser(user model.User) error {
 			team_name = EXCLUDED.team_name,
 			is_active = EXCLUDED.is_active,
 			updated_at = EXCLUDED.updated_at`,
-		user.UserID, user.Username, user.TeamName, user.IsActive, time.Now())
+		user.UserID, user.Username, user.TeamName, user.IsActive, time.Now().UTC())
 	return err
 }
 
-func (s *Storage) GetUser(userID string) (*model.User, error) {
+func (s *Storage) GetUser(ctx context.Context, userID string) (*model.User, error) {
+	ctx, done := observe(ctx, "GetUser")
+	defer done()
+
 	var user model.User
-	err := s.db.QueryRow(`
-		SELECT user_id, username, team_name, is_active 
+	err := s.db.QueryRowContext(ctx, `
+		SELECT user_id, username, team_name, is_active, max_open_reviews
 		FROM users WHERE user_id = $1`, userID).
-		Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+		Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.MaxOpenReviews)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -108,10 +215,46 @@ func (s *Storage) GetUser(userID string) (*model.User, error) {
 	return &user, nil
 }
 
-func (s *Storage) SetUserActive(userID string, isActive bool) error {
-	result, err := s.db.Exec(`
+func (s *Storage) LinkExternalAccount(ctx context.Context, account model.ExternalAccount) error {
+	ctx, done := observe(ctx, "LinkExternalAccount")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO external_accounts (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE SET
+			user_id = EXCLUDED.user_id`,
+		account.Provider, account.Login, account.UserID)
+	return err
+}
+
+func (s *Storage) GetUserByExternalLogin(ctx context.Context, provider, login string) (*model.User, error) {
+	ctx, done := observe(ctx, "GetUserByExternalLogin")
+	defer done()
+
+	var user model.User
+	err := s.db.QueryRowContext(ctx, `
+		SELECT u.user_id, u.username, u.team_name, u.is_active, u.max_open_reviews
+		FROM external_accounts ea
+		JOIN users u ON u.user_id = ea.user_id
+		WHERE ea.provider = $1 AND ea.login = $2`, provider, login).
+		Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.MaxOpenReviews)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *Storage) SetUserActive(ctx context.Context, userID string, isActive bool) error {
+	ctx, done := observe(ctx, "SetUserActive")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, `
 		UPDATE users SET is_active = $1, updated_at = $2 
-		WHERE user_id = $3`, isActive, time.Now(), userID)
+		WHERE user_id = $3`, isActive, time.Now().UTC(), userID)
 	if err != nil {
 		return err
 	}
@@ -125,12 +268,43 @@ func (s *Storage) SetUserActive(userID string, isActive bool) error {
 	return nil
 }
 
-func (s *Storage) GetActiveTeamMembers(teamName, excludeUserID string) ([]model.User, error) {
-	rows, err := s.db.Query(`
-		SELECT user_id, username, team_name, is_active 
+// SetUserMaxOpenReviews sets the personal open review limit of userID; nil
+// falls back to the team default.
+func (s *Storage) SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) error {
+	ctx, done := observe(ctx, "SetUserMaxOpenReviews")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE users SET max_open_reviews = $1, updated_at = $2
+		WHERE user_id = $3`, limit, time.Now().UTC(), userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetActiveTeamMembers returns the active members of teamName other than
+// excludeUserID that are not out of office on the date of at.
+func (s *Storage) GetActiveTeamMembers(ctx context.Context, teamName, excludeUserID string, at time.Time) ([]model.User, error) {
+	ctx, done := observe(ctx, "GetActiveTeamMembers")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, username, team_name, is_active, max_open_reviews
 		FROM users 
 		WHERE team_name = $1 AND is_active = true AND user_id != $2
-		ORDER BY user_id`, teamName, excludeUserID)
+			AND NOT EXISTS (
+				SELECT 1 FROM user_unavailability w
+				WHERE w.user_id = users.user_id AND $3::DATE BETWEEN w.start_date AND w.end_date
+			)
+		ORDER BY user_id`, teamName, excludeUserID, model.FormatDate(at))
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +313,7 @@ func (s *Storage) GetActiveTeamMembers(teamName, excludeUserID string) ([]model.
 	users := []model.User{}
 	for rows.Next() {
 		var u model.User
-		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews); err != nil {
 			return nil, err
 		}
 		users = append(users, u)
@@ -147,48 +321,88 @@ func (s *Storage) GetActiveTeamMembers(teamName, excludeUserID string) ([]model.
 	return users, nil
 }
 
-func (s *Storage) CreatePR(pr model.PullRequest) error {
-	tx, err := s.db.Begin()
+// CreatePR inserts the PR with its changed files and the reviewers listed in
+// pr.ReviewerSources and records events in the outbox within the same
+// transaction.
+func (s *Storage) CreatePR(ctx context.Context, pr model.PullRequest, events ...model.Event) error {
+	ctx, done := observe(ctx, "CreatePR")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	createdAt := time.Now()
-	_, err = tx.Exec(`
-		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at)
-		VALUES ($1, $2, $3, $4, $5)`,
-		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, createdAt)
+	createdAt := time.Now().UTC()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, reviewers_required)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, createdAt, pr.ReviewersRequired)
 	if err != nil {
 		return err
 	}
 
-	for _, reviewerID := range pr.AssignedReviewers {
-		_, err = tx.Exec(`
-			INSERT INTO pr_reviewers (pull_request_id, user_id)
-			VALUES ($1, $2)`, pr.PullRequestID, reviewerID)
+	for _, path := range pr.ChangedFiles {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pr_changed_files (pull_request_id, path)
+			VALUES ($1, $2)`, pr.PullRequestID, path)
 		if err != nil {
 			return err
 		}
 	}
 
+	if err := insertReviewers(ctx, tx, pr.PullRequestID, pr.ReviewerSources); err != nil {
+		return err
+	}
+
+	if err := insertOutbox(ctx, tx, events); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-func (s *Storage) PRExists(prID string) (bool, error) {
+// insertReviewers assigns reviewers to the PR and records each assignment
+// in the reviewer history.
+func insertReviewers(ctx context.Context, tx *sql.Tx, prID string, reviewers []model.ReviewerSource) error {
+	for _, r := range reviewers {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO pr_reviewers (pull_request_id, user_id, source, source_name, assigned_at)
+			VALUES ($1, $2, $3, $4, $5)`, prID, r.UserID, r.Source, r.Name, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		err = insertAssignment(ctx, tx, prID, r.UserID, model.AssignmentAssigned, model.ReasonAuto, "", "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) PRExists(ctx context.Context, prID string) (bool, error) {
+	ctx, done := observe(ctx, "PRExists")
+	defer done()
+
 	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", prID).Scan(&exists)
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", prID).Scan(&exists)
 	return exists, err
 }
 
-func (s *Storage) GetPR(prID string) (*model.PullRequest, error) {
+func (s *Storage) GetPR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	ctx, done := observe(ctx, "GetPR")
+	defer done()
+
 	var pr model.PullRequest
-	var createdAt, mergedAt sql.NullTime
+	var createdAt, mergedAt, closedAt sql.NullTime
 
-	err := s.db.QueryRow(`
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+	err := s.db.QueryRowContext(ctx, `
+		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at,
+			reviewers_required
 		FROM pull_requests WHERE pull_request_id = $1`, prID).
-		Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &mergedAt)
+		Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &mergedAt, &closedAt,
+			&pr.ReviewersRequired)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -204,9 +418,13 @@ func (s *Storage) GetPR(prID string) (*model.PullRequest, error) {
 		t := model.FormatTime(mergedAt.Time)
 		pr.MergedAt = &t
 	}
+	if closedAt.Valid {
+		t := model.FormatTime(closedAt.Time)
+		pr.ClosedAt = &t
+	}
 
-	rows, err := s.db.Query(`
-		SELECT user_id FROM pr_reviewers 
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, source, source_name FROM pr_reviewers 
 		WHERE pull_request_id = $1 
 		ORDER BY assigned_at`, prID)
 	if err != nil {
@@ -215,24 +433,116 @@ func (s *Storage) GetPR(prID string) (*model.PullRequest, error) {
 	defer rows.Close()
 
 	reviewers := []string{}
+	sources := []model.ReviewerSource{}
 	for rows.Next() {
-		var userID string
-		if err := rows.Scan(&userID); err != nil {
+		var r model.ReviewerSource
+		if err := rows.Scan(&r.UserID, &r.Source, &r.Name); err != nil {
 			return nil, err
 		}
-		reviewers = append(reviewers, userID)
+		reviewers = append(reviewers, r.UserID)
+		sources = append(sources, r)
 	}
 	pr.AssignedReviewers = reviewers
+	pr.ReviewerSources = sources
+
+	verdicts, err := s.getLatestVerdicts(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	pr.Reviews = verdicts
+
+	files, err := s.getChangedFiles(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	pr.ChangedFiles = files
 
 	return &pr, nil
 }
 
-func (s *Storage) MergePR(prID string) error {
-	mergedAt := time.Now()
-	result, err := s.db.Exec(`
+// getLatestVerdicts returns the most recent verdict of every reviewer still
+// assigned to the PR.
+func (s *Storage) getLatestVerdicts(ctx context.Context, prID string) ([]model.ReviewVerdict, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (r.user_id) r.user_id, r.verdict, r.created_at
+		FROM pr_reviews r
+		JOIN pr_reviewers pr ON pr.pull_request_id = r.pull_request_id AND pr.user_id = r.user_id
+		WHERE r.pull_request_id = $1
+		ORDER BY r.user_id, r.created_at DESC, r.id DESC`, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	verdicts := []model.ReviewVerdict{}
+	for rows.Next() {
+		var v model.ReviewVerdict
+		var submittedAt time.Time
+		if err := rows.Scan(&v.UserID, &v.Verdict, &submittedAt); err != nil {
+			return nil, err
+		}
+		v.SubmittedAt = model.FormatTime(submittedAt)
+		verdicts = append(verdicts, v)
+	}
+	return verdicts, rows.Err()
+}
+
+// AddReview stores a verdict from a reviewer assigned to an OPEN PR. It
+// returns sql.ErrNoRows if the reviewer is not assigned or the PR is not
+// open at the time of the insert.
+func (s *Storage) AddReview(ctx context.Context, review model.Review, events ...model.Event) (*model.Review, error) {
+	ctx, done := observe(ctx, "AddReview")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var submittedAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO pr_reviews (pull_request_id, user_id, verdict, comment, created_at)
+		SELECT $1::VARCHAR, $2::VARCHAR, $3::VARCHAR, $4::TEXT, $5::TIMESTAMP
+		WHERE EXISTS (
+			SELECT 1 FROM pr_reviewers pr
+			JOIN pull_requests p ON p.pull_request_id = pr.pull_request_id
+			WHERE pr.pull_request_id = $1 AND pr.user_id = $2 AND p.status = 'OPEN'
+		)
+		RETURNING id, created_at`,
+		review.PullRequestID, review.UserID, review.Verdict, review.Comment, time.Now().UTC()).
+		Scan(&review.ID, &submittedAt)
+	if err != nil {
+		return nil, err
+	}
+	review.SubmittedAt = model.FormatTime(submittedAt)
+
+	if err := insertOutbox(ctx, tx, events); err != nil {
+		return nil, err
+	}
+
+	return &review, tx.Commit()
+}
+
+// MergePR marks the OPEN PR as merged. A non-nil override is stored in the
+// same transaction as the audit record of a forced merge. It returns
+// sql.ErrNoRows if the PR is not OPEN, so that a PR closed concurrently is
+// not merged.
+func (s *Storage) MergePR(ctx context.Context, prID string, override *model.MergeOverride, events ...model.Event) error {
+	ctx, done := observe(ctx, "MergePR")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	mergedAt := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, `
 		UPDATE pull_requests 
 		SET status = $1, merged_at = $2 
-		WHERE pull_request_id = $3`, model.StatusMerged, mergedAt, prID)
+		WHERE pull_request_id = $3 AND status = $4`, model.StatusMerged, mergedAt, prID, model.StatusOpen)
 	if err != nil {
 		return err
 	}
@@ -243,17 +553,78 @@ func (s *Storage) MergePR(prID string) error {
 	if rows == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+
+	if override != nil {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO merge_overrides (pull_request_id, forced_by, reason, approvals, approvals_required, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			prID, override.ForcedBy, override.Reason, override.Approvals, override.ApprovalsRequired, mergedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertOutbox(ctx, tx, events); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListMergeOverrides returns the most recent forced merges, newest first.
+func (s *Storage) ListMergeOverrides(ctx context.Context, limit int) ([]model.MergeOverride, error) {
+	ctx, done := observe(ctx, "ListMergeOverrides")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, pull_request_id, forced_by, reason, approvals, approvals_required, created_at
+		FROM merge_overrides
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []model.MergeOverride{}
+	for rows.Next() {
+		var o model.MergeOverride
+		var createdAt time.Time
+		if err := rows.Scan(&o.ID, &o.PullRequestID, &o.ForcedBy, &o.Reason, &o.Approvals, &o.ApprovalsRequired, &createdAt); err != nil {
+			return nil, err
+		}
+		o.CreatedAt = model.FormatTime(createdAt)
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
 }
 
-func (s *Storage) ReassignReviewer(prID, oldUserID, newUserID string) error {
-	tx, err := s.db.Begin()
+// ReassignReviewer replaces oldUserID with newReviewer on the PR and records
+// both sides of the swap in the reviewer history with reason and actorID.
+func (s *Storage) ReassignReviewer(ctx context.Context, prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string, events ...model.Event) error {
+	ctx, done := observe(ctx, "ReassignReviewer")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	result, err := tx.Exec(`
+	if err := reassignReviewer(ctx, tx, prID, oldUserID, newReviewer, reason, actorID); err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, tx, events); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// reassignReviewer performs the swap of ReassignReviewer within tx. It
+// returns sql.ErrNoRows if oldUserID is not assigned to the PR.
+func reassignReviewer(ctx context.Context, tx *sql.Tx, prID, oldUserID string, newReviewer model.ReviewerSource, reason, actorID string) error {
+	result, err := tx.ExecContext(ctx, `
 		DELETE FROM pr_reviewers 
 		WHERE pull_request_id = $1 AND user_id = $2`, prID, oldUserID)
 	if err != nil {
@@ -267,22 +638,75 @@ func (s *Storage) ReassignReviewer(prID, oldUserID, newUserID string) error {
 		return sql.ErrNoRows
 	}
 
-	_, err = tx.Exec(`
-		INSERT INTO pr_reviewers (pull_request_id, user_id)
-		VALUES ($1, $2)`, prID, newUserID)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pr_reviewers (pull_request_id, user_id, source, source_name, assigned_at)
+		VALUES ($1, $2, $3, $4, $5)`, prID, newReviewer.UserID, newReviewer.Source, newReviewer.Name, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	err = insertAssignment(ctx, tx, prID, oldUserID, model.AssignmentUnassigned, reason, actorID, "")
+	if err != nil {
+		return err
+	}
+	return insertAssignment(ctx, tx, prID, newReviewer.UserID, model.AssignmentReassigned, reason, actorID, oldUserID)
+}
+
+// TransitionPR moves the PR from status from to status to and assigns the
+// given reviewers. A non-zero reviewersRequired replaces the PR's stored
+// reviewer count. It returns sql.ErrNoRows if the PR is not in status from,
+// so concurrent transitions of the same PR cannot both succeed.
+func (s *Storage) TransitionPR(ctx context.Context, prID, from, to string, reviewersRequired int, reviewers []model.ReviewerSource, events ...model.Event) error {
+	ctx, done := observe(ctx, "TransitionPR")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var closedAt *time.Time
+	if to == model.StatusClosed {
+		now := time.Now().UTC()
+		closedAt = &now
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE pull_requests
+		SET status = $1, closed_at = $2,
+			reviewers_required = CASE WHEN $5 > 0 THEN $5 ELSE reviewers_required END
+		WHERE pull_request_id = $3 AND status = $4`, to, closedAt, prID, from, reviewersRequired)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := insertReviewers(ctx, tx, prID, reviewers); err != nil {
+		return err
+	}
+
+	if err := insertOutbox(ctx, tx, events); err != nil {
+		return err
+	}
 
 	return tx.Commit()
 }
 
-func (s *Storage) GetPRsByReviewer(userID string) ([]model.PullRequestShort, error) {
-	rows, err := s.db.Query(`
+func (s *Storage) GetPRsByReviewer(ctx context.Context, userID string) ([]model.PullRequestShort, error) {
+	ctx, done := observe(ctx, "GetPRsByReviewer")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status
 		FROM pull_requests p
 		JOIN pr_reviewers pr ON p.pull_request_id = pr.pull_request_id
-		WHERE pr.user_id = $1
+		WHERE pr.user_id = $1 AND p.status <> 'CLOSED'
 		ORDER BY p.created_at DESC`, userID)
 	if err != nil {
 		return nil, err
@@ -300,63 +724,20 @@ func (s *Storage) GetPRsByReviewer(userID string) ([]model.PullRequestShort, err
 	return prs, nil
 }
 
-func (s *Storage) GetStatistics() (map[string]interface{}, error) {
-	stats := make(map[string]interface{})
-
-	var totalPRs, openPRs, mergedPRs int
-	err := s.db.QueryRow(`
-		SELECT 
-			COUNT(*) as total,
-			COUNT(*) FILTER (WHERE status = 'OPEN') as open,
-			COUNT(*) FILTER (WHERE status = 'MERGED') as merged
-		FROM pull_requests`).Scan(&totalPRs, &openPRs, &mergedPRs)
-	if err != nil {
-		return nil, err
-	}
-
-	stats["total_prs"] = totalPRs
-	stats["open_prs"] = openPRs
-	stats["merged_prs"] = mergedPRs
+// DeactivateTeam deactivates every active member of teamName and returns
+// their ids. If any were deactivated, the events built by eventsFor from
+// those ids are written to the outbox in the same transaction.
+func (s *Storage) DeactivateTeam(ctx context.Context, teamName string, eventsFor func(deactivatedUserIDs []string) []model.Event) ([]string, error) {
+	ctx, done := observe(ctx, "DeactivateTeam")
+	defer done()
 
-	rows, err := s.db.Query(`
-		SELECT u.user_id, u.username, COUNT(pr.pull_request_id) as review_count
-		FROM users u
-		LEFT JOIN pr_reviewers pr ON u.user_id = pr.user_id
-		GROUP BY u.user_id, u.username
-		HAVING COUNT(pr.pull_request_id) > 0
-		ORDER BY review_count DESC
-		LIMIT 10`)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	topReviewers := []map[string]interface{}{}
-	for rows.Next() {
-		var userID, username string
-		var count int
-		if err := rows.Scan(&userID, &username, &count); err != nil {
-			return nil, err
-		}
-		topReviewers = append(topReviewers, map[string]interface{}{
-			"user_id":      userID,
-			"username":     username,
-			"review_count": count,
-		})
-	}
-	stats["top_reviewers"] = topReviewers
-
-	return stats, nil
-}
-
-func (s *Storage) DeactivateTeam(teamName string) ([]string, error) {
-	tx, err := s.db.Begin()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	rows, err := tx.Query(`SELECT user_id FROM users WHERE team_name = $1 AND is_active = true`, teamName)
+	rows, err := tx.QueryContext(ctx, `SELECT user_id FROM users WHERE team_name = $1 AND is_active = true`, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -376,39 +757,39 @@ func (s *Storage) DeactivateTeam(teamName string) ([]string, error) {
 		return userIDs, nil
 	}
 
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		UPDATE users 
 		SET is_active = false, updated_at = $1 
-		WHERE team_name = $2 AND is_active = true`, time.Now(), teamName)
+		WHERE team_name = $2 AND is_active = true`, time.Now().UTC(), teamName)
 	if err != nil {
 		return nil, err
 	}
 
+	if eventsFor != nil {
+		if err := insertOutbox(ctx, tx, eventsFor(userIDs)); err != nil {
+			return nil, err
+		}
+	}
+
 	return userIDs, tx.Commit()
 }
 
-func (s *Storage) GetOpenPRsForReviewers(userIDs []string) ([]string, error) {
+func (s *Storage) GetOpenPRsForReviewers(ctx context.Context, userIDs []string) ([]string, error) {
+	ctx, done := observe(ctx, "GetOpenPRsForReviewers")
+	defer done()
+
 	if len(userIDs) == 0 {
 		return []string{}, nil
 	}
 
+	inClause, args := buildInClause(1, userIDs)
 	query := `
 		SELECT DISTINCT p.pull_request_id
 		FROM pull_requests p
 		JOIN pr_reviewers pr ON p.pull_request_id = pr.pull_request_id
-		WHERE p.status = 'OPEN' AND pr.user_id IN (`
-
-	args := []interface{}{}
-	for i, userID := range userIDs {
-		if i > 0 {
-			query += ", "
-		}
-		query += fmt.Sprintf("$%d", i+1)
-		args = append(args, userID)
-	}
-	query += ")"
+		WHERE p.status = 'OPEN' AND pr.user_id IN ` + inClause
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -424,3 +805,113 @@ func (s *Storage) GetOpenPRsForReviewers(userIDs []string) ([]string, error) {
 	}
 	return prIDs, nil
 }
+
+func (s *Storage) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	ctx, done := observe(ctx, "GetOpenReviewCounts")
+	defer done()
+
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	inClause, args := buildInClause(1, userIDs)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pr.user_id, COUNT(*)
+		FROM pr_reviewers pr
+		JOIN pull_requests p ON p.pull_request_id = pr.pull_request_id
+		WHERE p.status = 'OPEN' AND pr.user_id IN `+inClause+`
+		GROUP BY pr.user_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	return counts, rows.Err()
+}
+
+// GetLastAssignedReviewer returns whichever of userIDs was most recently
+// given a review, by assignment or reassignment, or "" if none ever was.
+func (s *Storage) GetLastAssignedReviewer(ctx context.Context, userIDs []string) (string, error) {
+	ctx, done := observe(ctx, "GetLastAssignedReviewer")
+	defer done()
+
+	if len(userIDs) == 0 {
+		return "", nil
+	}
+
+	inClause, args := buildInClause(3, userIDs)
+	args = append([]interface{}{model.AssignmentAssigned, model.AssignmentReassigned}, args...)
+	var userID string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT user_id
+		FROM reviewer_assignments
+		WHERE action IN ($1, $2) AND user_id IN `+inClause+`
+		ORDER BY id DESC
+		LIMIT 1`, args...).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return userID, err
+}
+
+// buildInClause renders "($start, $start+1, ...)" for use in an IN predicate
+// together with the matching positional arguments.
+func buildInClause(start int, values []string) (string, []interface{}) {
+	clause := "("
+	args := make([]interface{}, 0, len(values))
+	for i, value := range values {
+		if i > 0 {
+			clause += ", "
+		}
+		clause += fmt.Sprintf("$%d", start+i)
+		args = append(args, value)
+	}
+	return clause + ")", args
+}
+
+// ListUnderReviewedPRs returns the open PRs whose active reviewers are fewer
+// than their reviewers_required, oldest first. An empty teamName lists PRs
+// of every team; otherwise only PRs authored by members of teamName.
+func (s *Storage) ListUnderReviewedPRs(ctx context.Context, teamName string) ([]model.UnderReviewedPR, error) {
+	ctx, done := observe(ctx, "ListUnderReviewedPRs")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, a.team_name, p.reviewers_required,
+			COALESCE(string_agg(r.user_id, ',' ORDER BY r.user_id) FILTER (WHERE ru.is_active), '')
+		FROM pull_requests p
+		JOIN users a ON a.user_id = p.author_id
+		LEFT JOIN pr_reviewers r ON r.pull_request_id = p.pull_request_id
+		LEFT JOIN users ru ON ru.user_id = r.user_id
+		WHERE p.status = 'OPEN' AND ($1 = '' OR a.team_name = $1)
+		GROUP BY p.pull_request_id, a.team_name
+		HAVING COUNT(r.user_id) FILTER (WHERE ru.is_active) < p.reviewers_required
+		ORDER BY p.created_at, p.pull_request_id`, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs := []model.UnderReviewedPR{}
+	for rows.Next() {
+		var pr model.UnderReviewedPR
+		var activeReviewers string
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.TeamName,
+			&pr.ReviewersRequired, &activeReviewers); err != nil {
+			return nil, err
+		}
+		pr.ActiveReviewers = splitList(activeReviewers)
+		pr.Missing = pr.ReviewersRequired - len(pr.ActiveReviewers)
+		prs = append(prs, pr)
+	}
+	return prs, rows.Err()
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func (s *Storage) CreateUnavailability(ctx context.Context, u model.Unavailability) (*model.Unavailability, error) {
+	ctx, done := observe(ctx, "CreateUnavailability")
+	defer done()
+
+	var startDate, endDate, createdAt time.Time
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO user_unavailability (user_id, start_date, end_date, reason)
+		VALUES ($1, $2::DATE, $3::DATE, $4)
+		RETURNING id, start_date, end_date, created_at`,
+		u.UserID, u.StartDate, u.EndDate, u.Reason).
+		Scan(&u.ID, &startDate, &endDate, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	u.StartDate = model.FormatDate(startDate)
+	u.EndDate = model.FormatDate(endDate)
+	u.CreatedAt = model.FormatTime(createdAt)
+	return &u, nil
+}
+
+// ListUnavailability returns the windows of userID ordered by start date.
+func (s *Storage) ListUnavailability(ctx context.Context, userID string) ([]model.Unavailability, error) {
+	ctx, done := observe(ctx, "ListUnavailability")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, start_date, end_date, reason, created_at, reviews_reassigned_at
+		FROM user_unavailability
+		WHERE user_id = $1
+		ORDER BY start_date, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUnavailability(rows)
+}
+
+// ListUnreassignedUnavailability returns the windows of active users that
+// cover day and whose reviews have not been reassigned yet, ordered by
+// user and start date.
+func (s *Storage) ListUnreassignedUnavailability(ctx context.Context, day time.Time) ([]model.Unavailability, error) {
+	ctx, done := observe(ctx, "ListUnreassignedUnavailability")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT w.id, w.user_id, w.start_date, w.end_date, w.reason, w.created_at, w.reviews_reassigned_at
+		FROM user_unavailability w
+		JOIN users u ON u.user_id = w.user_id
+		WHERE u.is_active = true AND w.reviews_reassigned_at IS NULL
+			AND $1::DATE BETWEEN w.start_date AND w.end_date
+		ORDER BY w.user_id, w.start_date, w.id`, model.FormatDate(day))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUnavailability(rows)
+}
+
+// MarkUnavailabilityReassigned records that the reviews of window id have
+// been handed over.
+func (s *Storage) MarkUnavailabilityReassigned(ctx context.Context, id int64) error {
+	ctx, done := observe(ctx, "MarkUnavailabilityReassigned")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE user_unavailability SET reviews_reassigned_at = $2
+		WHERE id = $1 AND reviews_reassigned_at IS NULL`, id, time.Now().UTC())
+	return err
+}
+
+func scanUnavailability(rows *sql.Rows) ([]model.Unavailability, error) {
+	windows := []model.Unavailability{}
+	for rows.Next() {
+		var u model.Unavailability
+		var startDate, endDate, createdAt time.Time
+		var reassignedAt sql.NullTime
+		if err := rows.Scan(&u.ID, &u.UserID, &startDate, &endDate, &u.Reason, &createdAt, &reassignedAt); err != nil {
+			return nil, err
+		}
+		u.StartDate = model.FormatDate(startDate)
+		u.EndDate = model.FormatDate(endDate)
+		u.CreatedAt = model.FormatTime(createdAt)
+		if reassignedAt.Valid {
+			at := model.FormatTime(reassignedAt.Time)
+			u.ReviewsReassignedAt = &at
+		}
+		windows = append(windows, u)
+	}
+	return windows, rows.Err()
+}
+
+func (s *Storage) DeleteUnavailability(ctx context.Context, id int64) error {
+	ctx, done := observe(ctx, "DeleteUnavailability")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM user_unavailability WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetUnavailableUserIDs returns the active users that have a window
+// covering day.
+func (s *Storage) GetUnavailableUserIDs(ctx context.Context, day time.Time) ([]string, error) {
+	ctx, done := observe(ctx, "GetUnavailableUserIDs")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT u.user_id
+		FROM user_unavailability w
+		JOIN users u ON u.user_id = w.user_id
+		WHERE u.is_active = true AND $1::DATE BETWEEN w.start_date AND w.end_date
+		ORDER BY u.user_id`, model.FormatDate(day))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
@@ -0,0 +1,329 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"pr-reviewer-service/internal/model"
+	"pr-reviewer-service/internal/tracing"
+)
+
+func (s *Storage) CreateWebhookSubscription(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	ctx, done := observe(ctx, "CreateWebhookSubscription")
+	defer done()
+
+	var createdAt time.Time
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, is_active)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		sub.URL, sub.Secret, strings.Join(sub.EventTypes, ","), sub.IsActive).
+		Scan(&sub.ID, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	sub.CreatedAt = model.FormatTime(createdAt)
+	return &sub, nil
+}
+
+func (s *Storage) ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	ctx, done := observe(ctx, "ListWebhookSubscriptions")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, url, secret, event_types, is_active, created_at
+		FROM webhook_subscriptions
+		ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []model.WebhookSubscription{}
+	for rows.Next() {
+		var sub model.WebhookSubscription
+		var eventTypes string
+		var createdAt time.Time
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Secret, &eventTypes, &sub.IsActive, &createdAt); err != nil {
+			return nil, err
+		}
+		sub.EventTypes = splitList(eventTypes)
+		sub.CreatedAt = model.FormatTime(createdAt)
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (s *Storage) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	ctx, done := observe(ctx, "DeleteWebhookSubscription")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// EnqueueWebhookEvent creates a pending delivery of event for every active
+// subscription interested in its type and returns how many were created.
+// Enqueuing the same event twice does not duplicate deliveries.
+func (s *Storage) EnqueueWebhookEvent(ctx context.Context, event model.Event) (int, error) {
+	ctx, done := observe(ctx, "EnqueueWebhookEvent")
+	defer done()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, next_attempt_at, trace_parent)
+		SELECT id, $1::VARCHAR, $2::VARCHAR, $3::TEXT, $4::TIMESTAMP, $5::VARCHAR
+		FROM webhook_subscriptions
+		WHERE is_active = true
+			AND (event_types = '' OR $2::VARCHAR = ANY(string_to_array(event_types, ',')))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		event.ID, event.Type, string(payload), time.Now().UTC(), tracing.TraceParent(ctx))
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries that are due
+// at now and pushes their next attempt lease into the future, so concurrent
+// workers do not pick the same rows.
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.PendingWebhookDelivery, error) {
+	ctx, done := observe(ctx, "ClaimWebhookDeliveries")
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.status, d.attempts,
+			d.next_attempt_at, d.last_error, d.created_at, d.payload, d.trace_parent, s.url, s.secret
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = 'PENDING' AND d.next_attempt_at <= $1
+		ORDER BY d.next_attempt_at
+		LIMIT $2
+		FOR UPDATE OF d SKIP LOCKED`, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []model.PendingWebhookDelivery{}
+	for rows.Next() {
+		var d model.PendingWebhookDelivery
+		var nextAttemptAt, createdAt time.Time
+		var lastError sql.NullString
+		var payload string
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Status, &d.Attempts,
+			&nextAttemptAt, &lastError, &createdAt, &payload, &d.TraceParent, &d.URL, &d.Secret); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		d.NextAttemptAt = model.FormatTime(nextAttemptAt)
+		d.CreatedAt = model.FormatTime(createdAt)
+		if lastError.Valid {
+			d.LastError = &lastError.String
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	rows.Close()
+
+	for _, d := range deliveries {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE webhook_deliveries SET next_attempt_at = $1
+			WHERE id = $2`, now.Add(lease).UTC(), d.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return deliveries, tx.Commit()
+}
+
+// RecordWebhookAttempt appends an attempt to the delivery log and moves the
+// delivery to result.Status, copying it into the dead-letter table when it
+// has been given up on.
+func (s *Storage) RecordWebhookAttempt(ctx context.Context, deliveryID int64, result model.WebhookAttemptResult) error {
+	ctx, done := observe(ctx, "RecordWebhookAttempt")
+	defer done()
+
+	result.AttemptedAt = result.AttemptedAt.UTC()
+	result.NextAttemptAt = result.NextAttemptAt.UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		deliveryID, result.Attempt, result.StatusCode, result.Error, result.Duration.Milliseconds(), result.AttemptedAt)
+	if err != nil {
+		return err
+	}
+
+	var deliveredAt *time.Time
+	if result.Status == model.DeliveryDelivered {
+		deliveredAt = &result.AttemptedAt
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, delivered_at = $5
+		WHERE id = $6`,
+		result.Status, result.Attempt, result.NextAttemptAt, result.Error, deliveredAt, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	if result.Status == model.DeliveryDead {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO webhook_dead_letters (delivery_id, subscription_id, event_type, payload, attempts, last_error, failed_at)
+			SELECT id, subscription_id, event_type, payload, attempts, last_error, $1
+			FROM webhook_deliveries WHERE id = $2
+			ON CONFLICT (delivery_id) DO NOTHING`, result.AttemptedAt, deliveryID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListWebhookDeliveries returns the most recent deliveries, optionally
+// filtered by subscription (0 for all) and status ("" for all).
+func (s *Storage) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status string, limit int) ([]model.WebhookDelivery, error) {
+	ctx, done := observe(ctx, "ListWebhookDeliveries")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, subscription_id, event_id, event_type, status, attempts,
+			next_attempt_at, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE ($1::BIGINT = 0 OR subscription_id = $1) AND ($2::VARCHAR = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3`, subscriptionID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		var d model.WebhookDelivery
+		var nextAttemptAt, createdAt time.Time
+		var deliveredAt sql.NullTime
+		var lastError sql.NullString
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Status, &d.Attempts,
+			&nextAttemptAt, &lastError, &createdAt, &deliveredAt); err != nil {
+			return nil, err
+		}
+		d.NextAttemptAt = model.FormatTime(nextAttemptAt)
+		d.CreatedAt = model.FormatTime(createdAt)
+		if lastError.Valid {
+			d.LastError = &lastError.String
+		}
+		if deliveredAt.Valid {
+			t := model.FormatTime(deliveredAt.Time)
+			d.DeliveredAt = &t
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *Storage) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]model.WebhookDeliveryAttempt, error) {
+	ctx, done := observe(ctx, "ListWebhookDeliveryAttempts")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, delivery_id, attempt, status_code, error, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempt`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []model.WebhookDeliveryAttempt{}
+	for rows.Next() {
+		var a model.WebhookDeliveryAttempt
+		var statusCode sql.NullInt64
+		var errMsg sql.NullString
+		var attemptedAt time.Time
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.Attempt, &statusCode, &errMsg, &a.DurationMs, &attemptedAt); err != nil {
+			return nil, err
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			a.StatusCode = &code
+		}
+		if errMsg.Valid {
+			a.Error = &errMsg.String
+		}
+		a.AttemptedAt = model.FormatTime(attemptedAt)
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+func (s *Storage) ListWebhookDeadLetters(ctx context.Context, limit int) ([]model.WebhookDeadLetter, error) {
+	ctx, done := observe(ctx, "ListWebhookDeadLetters")
+	defer done()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT delivery_id, subscription_id, event_type, payload, attempts, last_error, failed_at
+		FROM webhook_dead_letters
+		ORDER BY failed_at DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	letters := []model.WebhookDeadLetter{}
+	for rows.Next() {
+		var l model.WebhookDeadLetter
+		var lastError sql.NullString
+		var failedAt time.Time
+		if err := rows.Scan(&l.DeliveryID, &l.SubscriptionID, &l.EventType, &l.Payload, &l.Attempts, &lastError, &failedAt); err != nil {
+			return nil, err
+		}
+		if lastError.Valid {
+			l.LastError = &lastError.String
+		}
+		l.FailedAt = model.FormatTime(failedAt)
+		letters = append(letters, l)
+	}
+	return letters, rows.Err()
+}
+
+// splitList parses a comma separated column such as event_types.
+func splitList(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+	return strings.Split(list, ",")
+}
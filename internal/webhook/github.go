@@ -0,0 +1,95 @@
+// Package webhook verifies and decodes incoming code hosting webhooks into
+// provider-neutral model.PullRequestEvent values.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"pr-reviewer-service/internal/model"
+)
+
+const githubSignaturePrefix = "sha256="
+
+type githubPullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// VerifyGitHubSignature checks the X-Hub-Signature-256 header value against
+// the HMAC-SHA256 of body keyed with secret.
+func VerifyGitHubSignature(secret, body []byte, signature string) bool {
+	if len(secret) == 0 || !strings.HasPrefix(signature, githubSignaturePrefix) {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, githubSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// Sign returns the "sha256=<hex hmac>" signature of body. It is the format
+// GitHub uses for X-Hub-Signature-256 and the one the service uses to sign
+// its own outgoing webhooks.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return githubSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseGitHubEvent decodes a GitHub webhook delivery. It returns nil without
+// an error for event types and actions the service does not act upon.
+func ParseGitHubEvent(eventType string, body []byte) (*model.PullRequestEvent, error) {
+	if eventType != "pull_request" {
+		return nil, nil
+	}
+
+	var payload githubPullRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode pull_request payload: %w", err)
+	}
+	if payload.Repository.FullName == "" || payload.Number == 0 {
+		return nil, fmt.Errorf("pull_request payload is missing repository or number")
+	}
+
+	var action string
+	switch payload.Action {
+	case "opened":
+		action = model.EventOpened
+	case "reopened":
+		action = model.EventReopened
+	case "closed":
+		action = model.EventClosed
+		if payload.PullRequest.Merged {
+			action = model.EventMerged
+		}
+	default:
+		return nil, nil
+	}
+
+	return &model.PullRequestEvent{
+		Provider:        model.ProviderGitHub,
+		Action:          action,
+		PullRequestID:   fmt.Sprintf("%s#%d", payload.Repository.FullName, payload.Number),
+		PullRequestName: payload.PullRequest.Title,
+		AuthorLogin:     payload.PullRequest.User.Login,
+	}, nil
+}
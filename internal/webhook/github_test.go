@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	secret := []byte("It's a Secret to Everybody")
+	body := []byte("Hello, World!")
+
+	// Reference value from the GitHub webhook documentation.
+	valid := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	if !VerifyGitHubSignature(secret, body, valid) {
+		t.Error("Expected documented signature to verify")
+	}
+	if Sign(secret, body) != valid {
+		t.Error("Sign does not match documented signature")
+	}
+
+	cases := map[string]string{
+		"Empty":      "",
+		"NoPrefix":   "757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		"SHA1":       "sha1=01dc10d0c83e72ed246219cdd91669667fe2ca59",
+		"NotHex":     "sha256=zz",
+		"WrongValue": "sha256=0000000000000000000000000000000000000000000000000000000000000000",
+	}
+	for name, signature := range cases {
+		t.Run(name, func(t *testing.T) {
+			if VerifyGitHubSignature(secret, body, signature) {
+				t.Errorf("Signature %q should be rejected", signature)
+			}
+		})
+	}
+
+	if VerifyGitHubSignature(nil, body, valid) {
+		t.Error("Empty secret must never verify")
+	}
+}
+
+func TestParseGitHubEvent(t *testing.T) {
+	cases := []struct {
+		fixture string
+		event   string
+		action  string
+	}{
+		{"github_pull_request_opened.json", "pull_request", model.EventOpened},
+		{"github_pull_request_merged.json", "pull_request", model.EventMerged},
+		{"github_pull_request_closed.json", "pull_request", model.EventClosed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			ev, err := ParseGitHubEvent(tc.event, readFixture(t, tc.fixture))
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			if ev == nil {
+				t.Fatal("Expected an event")
+			}
+			if ev.Action != tc.action {
+				t.Errorf("Expected action %s, got %s", tc.action, ev.Action)
+			}
+			if ev.PullRequestID != "acme/backend#42" {
+				t.Errorf("Unexpected PR id %s", ev.PullRequestID)
+			}
+			if ev.AuthorLogin != "Alice-Dev" {
+				t.Errorf("Unexpected author %s", ev.AuthorLogin)
+			}
+		})
+	}
+
+	ev, err := ParseGitHubEvent("ping", readFixture(t, "github_ping.json"))
+	if err != nil || ev != nil {
+		t.Errorf("Expected ping to be ignored, got %v, %v", ev, err)
+	}
+
+	if _, err := ParseGitHubEvent("pull_request", []byte("{")); err == nil {
+		t.Error("Expected malformed payload to fail")
+	}
+}
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+
+	"pr-reviewer-service/internal/model"
+)
+
+const gitlabMergeRequestEvent = "Merge Request Hook"
+
+type gitlabMergeRequestPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// VerifyGitLabToken compares the X-Gitlab-Token header with the configured
+// secret token in constant time.
+func VerifyGitLabToken(secret, token string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
+
+// ParseGitLabEvent decodes a GitLab webhook delivery. It returns nil without
+// an error for event types and actions the service does not act upon.
+//
+// GitLab merge request hooks identify the user who triggered the event, not
+// the MR author. Only on "open" is that the author, so AuthorLogin is left
+// empty for every other action and an MR the service has not seen is only
+// created from its "open" event.
+func ParseGitLabEvent(eventType string, body []byte) (*model.PullRequestEvent, error) {
+	if eventType != gitlabMergeRequestEvent {
+		return nil, nil
+	}
+
+	var payload gitlabMergeRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode merge_request payload: %w", err)
+	}
+	if payload.ObjectKind != "merge_request" {
+		return nil, nil
+	}
+	if payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID == 0 {
+		return nil, fmt.Errorf("merge_request payload is missing project or iid")
+	}
+
+	var action string
+	switch payload.ObjectAttributes.Action {
+	case "open":
+		action = model.EventOpened
+	case "reopen":
+		action = model.EventReopened
+	case "update":
+		action = model.EventUpdated
+	case "merge":
+		action = model.EventMerged
+	case "close":
+		action = model.EventClosed
+	default:
+		return nil, nil
+	}
+	if action == model.EventUpdated && payload.ObjectAttributes.State != "opened" {
+		return nil, nil
+	}
+
+	ev := &model.PullRequestEvent{
+		Provider:        model.ProviderGitLab,
+		Action:          action,
+		PullRequestID:   fmt.Sprintf("%s!%d", payload.Project.PathWithNamespace, payload.ObjectAttributes.IID),
+		PullRequestName: payload.ObjectAttributes.Title,
+	}
+	if action == model.EventOpened {
+		ev.AuthorLogin = payload.User.Username
+	}
+	return ev, nil
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"testing"
+
+	"pr-reviewer-service/internal/model"
+)
+
+func TestVerifyGitLabToken(t *testing.T) {
+	if !VerifyGitLabToken("s3cret", "s3cret") {
+		t.Error("Expected matching token to verify")
+	}
+	if VerifyGitLabToken("s3cret", "S3cret") {
+		t.Error("Expected mismatching token to be rejected")
+	}
+	if VerifyGitLabToken("", "") {
+		t.Error("Empty secret must never verify")
+	}
+}
+
+func TestParseGitLabEvent(t *testing.T) {
+	cases := []struct {
+		fixture string
+		action  string
+		author  string
+	}{
+		{"gitlab_merge_request_open.json", model.EventOpened, "alice.dev"},
+		// The triggering user of later actions is not necessarily the author.
+		{"gitlab_merge_request_update.json", model.EventUpdated, ""},
+		{"gitlab_merge_request_merge.json", model.EventMerged, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			ev, err := ParseGitLabEvent("Merge Request Hook", readFixture(t, tc.fixture))
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			if ev == nil {
+				t.Fatal("Expected an event")
+			}
+			if ev.Action != tc.action {
+				t.Errorf("Expected action %s, got %s", tc.action, ev.Action)
+			}
+			if ev.PullRequestID != "platform/billing!7" {
+				t.Errorf("Unexpected PR id %s", ev.PullRequestID)
+			}
+			if ev.Provider != model.ProviderGitLab || ev.AuthorLogin != tc.author {
+				t.Errorf("Unexpected provider/author %s/%s", ev.Provider, ev.AuthorLogin)
+			}
+		})
+	}
+
+	ev, err := ParseGitLabEvent("Push Hook", readFixture(t, "gitlab_merge_request_open.json"))
+	if err != nil || ev != nil {
+		t.Errorf("Expected non-MR hooks to be ignored, got %v, %v", ev, err)
+	}
+}
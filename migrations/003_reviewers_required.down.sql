@@ -0,0 +1,2 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS max_reviewers;
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewers_required;
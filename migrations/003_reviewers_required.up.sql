@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS reviewers_required INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_required >= 1);
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS max_reviewers INTEGER NOT NULL DEFAULT 2 CHECK (max_reviewers >= 1);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS merge_overrides;
+ALTER TABLE teams DROP COLUMN IF EXISTS approvals_required;
@@ -0,0 +1,15 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS approvals_required INTEGER NOT NULL DEFAULT 0 CHECK (approvals_required >= 0);
+
+CREATE TABLE IF NOT EXISTS merge_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    forced_by VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    approvals INTEGER NOT NULL,
+    approvals_required INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_merge_overrides_created_at ON merge_overrides(created_at);
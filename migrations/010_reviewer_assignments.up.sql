@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS reviewer_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('assigned', 'unassigned', 'reassigned')),
+    reason VARCHAR(32) NOT NULL CHECK (reason IN ('auto', 'manual', 'team_deactivation', 'ooo')),
+    actor_id VARCHAR(255),
+    replaced_user_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_assignments_pr ON reviewer_assignments(pull_request_id, id);
+
+-- Backfill the current assignments of PRs created before the history existed.
+INSERT INTO reviewer_assignments (pull_request_id, user_id, action, reason, created_at)
+SELECT r.pull_request_id, r.user_id, 'assigned', 'auto', COALESCE(r.assigned_at, CURRENT_TIMESTAMP)
+FROM pr_reviewers r
+WHERE NOT EXISTS (
+    SELECT 1 FROM reviewer_assignments a
+    WHERE a.pull_request_id = r.pull_request_id AND a.user_id = r.user_id
+);
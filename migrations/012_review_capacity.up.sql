@@ -0,0 +1,9 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS max_open_reviews INTEGER NOT NULL DEFAULT 0 CHECK (max_open_reviews >= 0);
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS capacity_overflow VARCHAR(20) NOT NULL DEFAULT 'reject'
+        CHECK (capacity_overflow IN ('reject', 'least_loaded'));
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS max_open_reviews INTEGER CHECK (max_open_reviews >= 0);
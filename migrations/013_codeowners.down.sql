@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pr_changed_files;
+DROP TABLE IF EXISTS team_codeowners;
+ALTER TABLE teams DROP COLUMN IF EXISTS code_owners_mode;
@@ -0,0 +1,17 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS code_owners_mode VARCHAR(20) NOT NULL DEFAULT 'prefer'
+        CHECK (code_owners_mode IN ('prefer', 'require'));
+
+CREATE TABLE IF NOT EXISTS team_codeowners (
+    team_name VARCHAR(255) PRIMARY KEY,
+    content TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS pr_changed_files (
+    pull_request_id VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    PRIMARY KEY (pull_request_id, path),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE
+);
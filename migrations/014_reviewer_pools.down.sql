@@ -0,0 +1,5 @@
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS source_name;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS source;
+ALTER TABLE teams DROP COLUMN IF EXISTS fallback_pools;
+DROP TABLE IF EXISTS reviewer_pool_members;
+DROP TABLE IF EXISTS reviewer_pools;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS reviewer_pools (
+    pool_name VARCHAR(255) PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS reviewer_pool_members (
+    pool_name VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    PRIMARY KEY (pool_name, user_id),
+    FOREIGN KEY (pool_name) REFERENCES reviewer_pools(pool_name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_pool_members_user ON reviewer_pool_members(user_id);
+
+-- Comma separated pool names, tried in order.
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS fallback_pools TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'team';
+
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS source_name VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE pr_reviewers r
+SET source_name = u.team_name
+FROM users u
+WHERE u.user_id = r.user_id AND r.source_name = '';
@@ -0,0 +1,13 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS understaffed_policy VARCHAR(20) NOT NULL DEFAULT 'warn'
+        CHECK (understaffed_policy IN ('allow', 'warn', 'reject'));
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS reviewers_required INTEGER NOT NULL DEFAULT 0 CHECK (reviewers_required >= 0);
+
+-- PRs created before reviewer counts were stored require their team's count.
+UPDATE pull_requests p
+SET reviewers_required = t.reviewers_required
+FROM users u
+JOIN teams t ON t.team_name = u.team_name
+WHERE u.user_id = p.author_id AND p.reviewers_required = 0 AND p.status <> 'DRAFT';
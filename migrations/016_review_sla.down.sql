@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS sla_breaches;
+
+-- The sla reason did not exist before this migration.
+UPDATE reviewer_assignments SET reason = 'manual' WHERE reason = 'sla';
+
+ALTER TABLE reviewer_assignments DROP CONSTRAINT IF EXISTS reviewer_assignments_reason_check;
+ALTER TABLE reviewer_assignments ADD CONSTRAINT reviewer_assignments_reason_check
+    CHECK (reason IN ('auto', 'manual', 'team_deactivation', 'ooo'));
+
+ALTER TABLE teams DROP COLUMN IF EXISTS team_lead_id;
+ALTER TABLE teams DROP COLUMN IF EXISTS sla_action;
+ALTER TABLE teams DROP COLUMN IF EXISTS review_sla_minutes;
@@ -0,0 +1,30 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS review_sla_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_minutes >= 0);
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS sla_action VARCHAR(20) NOT NULL DEFAULT 'escalate'
+        CHECK (sla_action IN ('escalate', 'reassign'));
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS team_lead_id VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE reviewer_assignments DROP CONSTRAINT IF EXISTS reviewer_assignments_reason_check;
+ALTER TABLE reviewer_assignments ADD CONSTRAINT reviewer_assignments_reason_check
+    CHECK (reason IN ('auto', 'manual', 'team_deactivation', 'ooo', 'sla'));
+
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    reviewer_id VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    assigned_at TIMESTAMP NOT NULL,
+    sla_minutes INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('escalated', 'reassigned')),
+    new_reviewer_id VARCHAR(255),
+    team_lead_id VARCHAR(255),
+    detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pull_request_id, reviewer_id, assigned_at),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_team ON sla_breaches(team_name, id);
@@ -0,0 +1,7 @@
+-- W3C traceparent of the request that produced an outbox event or a
+-- webhook delivery, so that asynchronous work joins the original trace.
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS trace_parent VARCHAR(55) NOT NULL DEFAULT '';
+
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS trace_parent VARCHAR(55) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE user_unavailability DROP COLUMN IF EXISTS reviews_reassigned_at;
@@ -0,0 +1,4 @@
+-- When the OOO job handed the window owner's open reviews over, so that each
+-- window is processed once.
+ALTER TABLE user_unavailability
+    ADD COLUMN IF NOT EXISTS reviews_reassigned_at TIMESTAMP;